@@ -0,0 +1,165 @@
+/*
+Command predeval measures how well a move prediction model finds expert moves.
+
+It replays every .sgf file under a directory and reports top-k accuracy and
+the mean rank of the expert move, overall and split by game phase.
+
+	predeval -model model.txt -k 10 -phases 31,151 games/
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/gosharplite/goxit/pkg/movepred"
+	"github.com/gosharplite/goxit/pkg/sgf"
+)
+
+func main() {
+
+	model := flag.String("model", "", "model file written by movepred.Model.WriteTo")
+	k := flag.Int("k", 20, "largest rank in the accuracy curve")
+	phases := flag.String("phases", "31,151", "first move number of each phase after the opening")
+
+	flag.Parse()
+
+	if *model == "" || flag.NArg() != 1 {
+		usage("")
+	}
+
+	if *k <= 0 {
+		usage("k must be positive")
+	}
+
+	bounds, err := parsePhases(*phases)
+	if err != nil {
+		usage(err.Error())
+	}
+
+	f, err := os.Open(*model)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	m, err := movepred.ReadModel(f)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	files, err := sgf.Files(flag.Arg(0))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	e := movepred.NewEvaluation(&m, *k, bounds)
+
+	for _, path := range files {
+
+		roots, err := sgf.ParseFile(path)
+		if err != nil {
+			log.Printf("%s: %v", path, err)
+			continue
+		}
+
+		for _, root := range roots {
+			if err := e.AddGame(root); err != nil {
+				log.Printf("%s: %v", path, err)
+			}
+		}
+	}
+
+	fmt.Printf("files: %d positions: %d\n\n", len(files), e.Total.Positions)
+
+	report(&e, *k)
+}
+
+// usage prints an optional reason and the usage, then exits.
+func usage(reason string) {
+
+	if reason != "" {
+		fmt.Fprintln(os.Stderr, "predeval:", reason)
+	}
+
+	fmt.Fprintln(os.Stderr, "usage: predeval -model file [-k n] [-phases m,n] dir")
+	os.Exit(2)
+}
+
+// parsePhases parses the first move numbers of the phases after the opening,
+// which must increase from 2.
+func parsePhases(s string) ([]int, error) {
+
+	var r []int
+
+	if s == "" {
+		return r, nil
+	}
+
+	// Move numbers start at 1, the opening.
+	prev := 1
+
+	for _, v := range strings.Split(s, ",") {
+
+		n, err := strconv.Atoi(strings.TrimSpace(v))
+		if err != nil {
+			return nil, fmt.Errorf("bad phase %q", v)
+		}
+
+		if n <= prev {
+			return nil, fmt.Errorf("phase %d is not after %d", n, prev)
+		}
+
+		r = append(r, n)
+		prev = n
+	}
+
+	return r, nil
+}
+
+func report(e *movepred.Evaluation, k int) {
+
+	names := []string{"all"}
+
+	start := 1
+	for _, b := range e.Phases {
+		names = append(names, fmt.Sprintf("%d-%d", start, b-1))
+		start = b
+	}
+	names = append(names, fmt.Sprintf("%d-", start))
+
+	stats := append([]movepred.Stats{e.Total}, e.ByPhase...)
+
+	fmt.Printf("%-8s", "top-k")
+	for _, n := range names {
+		fmt.Printf("%10s", n)
+	}
+	fmt.Println()
+
+	for i := 1; i <= k; i++ {
+
+		fmt.Printf("%-8d", i)
+
+		for _, s := range stats {
+			fmt.Printf("%10.4f", s.Accuracy(i))
+		}
+
+		fmt.Println()
+	}
+
+	fmt.Printf("%-8s", "rank")
+	for _, s := range stats {
+		fmt.Printf("%10.2f", s.MeanRank())
+	}
+	fmt.Println()
+
+	fmt.Printf("%-8s", "n")
+	for _, s := range stats {
+		fmt.Printf("%10d", s.Positions)
+	}
+	fmt.Println()
+}
@@ -0,0 +1,3 @@
+module github.com/gosharplite/goxit
+
+go 1.21
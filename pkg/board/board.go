@@ -49,6 +49,19 @@ type Board struct {
 	chains    []*chain
 	chainReps []int
 
+	// Array length is boardSize. 3x3 neighborhood codes of points on the board.
+	codes []uint16
+
+	// Zobrist hashes of the stones seen through each symmetry, and their
+	// keys with array length boardSize*16.
+	zobrist     [8]uint64
+	zobristKeys []uint64
+
+	// Ring of the points changed by setState, and the number of changes
+	// ever made. See ChangedSince.
+	changes    []int
+	numChanges uint64
+
 	// Current ko point if exists, 0 otherwise
 	koPoint int
 
@@ -86,7 +99,17 @@ func (bd *Board) init() {
 
 	bd.chainReps = make([]int, bd.boardSize)
 
+	bd.codes = make([]uint16, bd.boardSize)
+
+	bd.changes = make([]int, changeLogFactor*bd.boardSize)
+
 	bd.initStates()
+
+	bd.initCodes()
+
+	bd.initZobristKeys()
+
+	bd.initZobrist()
 }
 
 func (bd *Board) initStates() {
@@ -148,6 +171,24 @@ func (bd *Board) String() string {
 	return r
 }
 
+// Size returns the number of lines on each side of the board.
+func (bd *Board) Size() int {
+
+	return bd.size
+}
+
+// Point returns the board index of the zero based coordinate (x, y).
+func (bd *Board) Point(x, y int) int {
+
+	return (y+1)*(bd.size+1) + x + 1
+}
+
+// XY returns the zero based coordinate of a board index.
+func (bd *Board) XY(pt int) (x, y int) {
+
+	return pt%(bd.size+1) - 1, pt/(bd.size+1) - 1
+}
+
 // DoBlack puts a black stone on a point.
 func (bd *Board) DoBlack(pt int) error {
 
@@ -160,6 +201,72 @@ func (bd *Board) DoWhite(pt int) error {
 	return bd.do(pt, white)
 }
 
+// Do puts a stone of a color on a point.
+func (bd *Board) Do(pt int, isBlack bool) error {
+
+	if isBlack {
+		return bd.do(pt, black)
+	}
+
+	return bd.do(pt, white)
+}
+
+// SetupBlack puts a black stone on a point as part of the initial position,
+// such as a handicap stone. It is undone like a move but is not a move, see
+// IsSetup.
+func (bd *Board) SetupBlack(pt int) error {
+
+	return bd.setup(pt, black)
+}
+
+// SetupWhite puts a white stone on a point as part of the initial position.
+func (bd *Board) SetupWhite(pt int) error {
+
+	return bd.setup(pt, white)
+}
+
+// PassBlack records a pass by black, undone like a move.
+func (bd *Board) PassBlack() error {
+
+	return bd.pass(black)
+}
+
+// PassWhite records a pass by white, undone like a move.
+func (bd *Board) PassWhite() error {
+
+	return bd.pass(white)
+}
+
+func (bd *Board) setup(pt int, clr state) error {
+
+	if err := bd.do(pt, clr); err != nil {
+		return err
+	}
+
+	bd.histories[bd.depth].setup = true
+
+	return nil
+}
+
+// pass records a pass on point 0. The ko point is cleared as the ban only
+// lasts one turn.
+func (bd *Board) pass(clr state) error {
+
+	if bd.depth >= bd.maxHistory {
+		return errors.New("depth is larger than maxHistory")
+	}
+
+	h := newHistory(clr, 0, bd.koPoint)
+
+	bd.koPoint = 0
+
+	bd.depth++
+
+	bd.histories[bd.depth] = &h
+
+	return nil
+}
+
 func (bd *Board) do(pt int, clr state) error {
 
 	err := bd.isLegal(pt, clr)
@@ -376,7 +483,7 @@ func (bd *Board) updateLibertiesAndChainReps(c *chain, clr state) {
 		pt := c.points[i]
 
 		// Update states, chains, chain_reps
-		bd.states[pt] = clr
+		bd.setState(pt, clr)
 
 		bd.chains[pt] = c
 
@@ -438,7 +545,7 @@ func (bd *Board) updateNeighboringChainsLiberties(c *chain) {
 
 func (bd *Board) setEmpty(pt int) {
 
-	bd.states[pt] = empty
+	bd.setState(pt, empty)
 	bd.chains[pt] = nil
 	bd.chainReps[pt] = 0
 }
@@ -471,7 +578,7 @@ func (bd *Board) updateLiberties(c *chain) {
 	}
 }
 
-// Undo remove the last stone placed on the Go board.
+// Undo remove the last stone placed on the Go board, or the last pass.
 func (bd *Board) Undo() error {
 
 	if bd.depth == 0 {
@@ -480,6 +587,15 @@ func (bd *Board) Undo() error {
 
 	h := bd.histories[bd.depth]
 
+	if h.point == 0 {
+
+		bd.koPoint = h.koPoint
+
+		bd.depth--
+
+		return nil
+	}
+
 	clr := h.color
 
 	pt := h.point
@@ -512,7 +628,7 @@ func (bd *Board) Undo() error {
 			c := bd.reconstructChain(n, empty, pt)
 
 			for j := 0; j < c.numPoints; j++ {
-				bd.states[c.points[j]] = np
+				bd.setState(c.points[j], np)
 			}
 
 			bd.updateLibertiesAndChainReps(&c, np)
@@ -73,6 +73,79 @@ func TestIsKo(t *testing.T) {
 	}
 }
 
+func TestPass(t *testing.T) {
+
+	bh := NewBoard(3)
+
+	bh.SetupBlack(5)
+	bh.SetupBlack(7)
+	bh.SetupBlack(10)
+
+	if !bh.IsSetup(3) {
+		t.Error("setup stone 3 not reported by IsSetup")
+	}
+
+	if bh.BlackToPlay() {
+		t.Error("black to play after black setup stones, expected white")
+	}
+
+	bh.DoWhite(9)
+	bh.DoWhite(6)
+
+	if bh.IsSetup(5) {
+		t.Error("move 5 reported as a setup stone")
+	}
+
+	if bh.KoPoint() != 5 {
+		t.Errorf("ko point %d after a capture, expected 5", bh.KoPoint())
+	}
+
+	bh.PassBlack()
+
+	if pt, isBlack := bh.Move(bh.Depth()); pt != 0 || !isBlack || bh.BlackToPlay() {
+		t.Errorf("pass recorded as %d by black %v", pt, isBlack)
+	}
+
+	if bh.IsSetup(bh.Depth()) {
+		t.Error("pass reported as a setup stone")
+	}
+
+	if bh.KoPoint() != 0 {
+		t.Error("ko point kept after a pass")
+	}
+
+	bh.Undo()
+
+	if bh.KoPoint() != 5 || !bh.BlackToPlay() {
+		t.Error("ko point not restored by undoing a pass")
+	}
+}
+
+func TestChangedSince(t *testing.T) {
+
+	bh := NewBoard(3)
+
+	bh.DoBlack(5)
+	since := bh.ChangeCount()
+
+	bh.DoWhite(6)
+	bh.Undo()
+
+	pts, ok := bh.ChangedSince(nil, since)
+	if !ok || len(pts) != 2 || pts[0] != 6 || pts[1] != 6 {
+		t.Errorf("changes %v %v, expected [6 6] true", pts, ok)
+	}
+
+	for i := 0; i < changeLogFactor*bh.boardSize; i++ {
+		bh.DoBlack(9)
+		bh.Undo()
+	}
+
+	if _, ok := bh.ChangedSince(nil, since); ok {
+		t.Error("changes kept beyond the log")
+	}
+}
+
 func TestIsSuicide(t *testing.T) {
 
 	bh := NewBoard(3)
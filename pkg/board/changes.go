@@ -0,0 +1,35 @@
+package board
+
+// changeLogFactor is the number of state changes kept per index of the board
+// array, so observers may fall a few moves behind.
+const changeLogFactor = 4
+
+// logChange records a point whose state changed.
+func (bd *Board) logChange(pt int) {
+
+	bd.changes[bd.numChanges%uint64(len(bd.changes))] = pt
+	bd.numChanges++
+}
+
+// ChangeCount returns the number of point state changes made by moves and
+// Undo since the board was created.
+func (bd *Board) ChangeCount() uint64 {
+
+	return bd.numChanges
+}
+
+// ChangedSince appends to dst the points whose state changed after the
+// change count since, repeats included. ok is false if those changes are no
+// longer kept and the whole board must be looked at.
+func (bd *Board) ChangedSince(dst []int, since uint64) (r []int, ok bool) {
+
+	if since > bd.numChanges || bd.numChanges-since > uint64(len(bd.changes)) {
+		return dst, false
+	}
+
+	for n := since; n < bd.numChanges; n++ {
+		dst = append(dst, bd.changes[n%uint64(len(bd.changes))])
+	}
+
+	return dst, true
+}
@@ -0,0 +1,88 @@
+package board
+
+// codeValues is the 2 bit cell value of each state in a 3x3 code, see
+// hash.Table3x3.
+var codeValues = [...]uint16{
+	black: 1,
+	white: 2,
+	empty: 0,
+	wall:  3,
+}
+
+// Code3x3 returns the 3x3 code of the 8 neighbors of a point, to be looked
+// up in a hash.Table3x3. It is kept up to date by every move and Undo.
+func (bd *Board) Code3x3(pt int) uint16 {
+
+	return bd.codes[pt]
+}
+
+// neighbors3x3 returns the 8 surrounding points clockwise from north, the
+// order of a 3x3 code.
+func (bd *Board) neighbors3x3(pt int) [8]int {
+
+	s := bd.size
+
+	return [8]int{
+		pt - (s + 1),
+		pt - s,
+		pt + 1,
+		pt + (s + 2),
+		pt + (s + 1),
+		pt + s,
+		pt - 1,
+		pt - (s + 2)}
+}
+
+func (bd *Board) initCodes() {
+
+	for pt, s := range bd.states {
+
+		if s == wall {
+			continue
+		}
+
+		var code uint16
+
+		for k, n := range bd.neighbors3x3(pt) {
+			code |= codeValues[bd.states[n]] << uint(2*k)
+		}
+
+		bd.codes[pt] = code
+	}
+}
+
+// setState changes the state of a point, the codes of its neighbors and the
+// Zobrist hashes, and logs the change.
+func (bd *Board) setState(pt int, s state) {
+
+	old := bd.states[pt]
+	if old == s {
+		return
+	}
+
+	bd.states[pt] = s
+
+	bd.logChange(pt)
+
+	if old == black || old == white {
+		bd.xorStone(pt, old)
+	}
+
+	if s == black || s == white {
+		bd.xorStone(pt, s)
+	}
+
+	v := codeValues[s]
+
+	for k, n := range bd.neighbors3x3(pt) {
+
+		if bd.states[n] == wall {
+			continue
+		}
+
+		// pt is the opposite neighbor of n.
+		shift := uint(2 * ((k + 4) % 8))
+
+		bd.codes[n] = bd.codes[n]&^(3<<shift) | v<<shift
+	}
+}
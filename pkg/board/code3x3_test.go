@@ -0,0 +1,72 @@
+package board
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/hash"
+)
+
+func TestCode3x3(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(3))
+
+	bh := NewBoard(7)
+
+	fresh := func() []uint16 {
+
+		c := NewBoard(7)
+
+		for pt, s := range bh.states {
+			if s != wall {
+				c.states[pt] = s
+			}
+		}
+
+		c.initCodes()
+
+		return c.codes
+	}
+
+	check := func(when string) {
+
+		expected := fresh()
+
+		for pt, s := range bh.states {
+			if s != wall && bh.Code3x3(pt) != expected[pt] {
+				t.Fatalf("%s: point %d code %#x, expected %#x\n%s", when, pt, bh.Code3x3(pt), expected[pt], bh.String())
+			}
+		}
+	}
+
+	check("new board")
+
+	if c := bh.Code3x3(bh.Point(0, 0)); c != 0xfc0f {
+		t.Errorf("corner code %#x, expected 0xfc0f", c)
+	}
+
+	moves := bh.playout(rnd)
+
+	check("after playout")
+
+	for j := 0; j < moves; j++ {
+
+		bh.Undo()
+
+		check("after undo")
+	}
+}
+
+func TestCode3x3Pattern(t *testing.T) {
+
+	bh := NewBoard(5)
+
+	bh.DoBlack(bh.Point(0, 1))
+	bh.DoWhite(bh.Point(1, 0))
+
+	pt := bh.Point(0, 0)
+
+	if c := hash.Code3x3(bh.Pattern(pt, 3)); c != bh.Code3x3(pt) {
+		t.Errorf("pattern code %#x, board code %#x", c, bh.Code3x3(pt))
+	}
+}
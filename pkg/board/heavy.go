@@ -0,0 +1,289 @@
+package board
+
+import (
+	"math/rand"
+	"strings"
+
+	"github.com/gosharplite/goxit/pkg/hash"
+)
+
+// mogoPatterns are the 3x3 playout patterns of MoGo, the move at the centre
+// and the player to move black. They also apply with colors swapped. Cells
+// MoGo does not care about may be off the board.
+var mogoPatterns = []string{
+	// Hane.
+	"XOX ... ***",
+	"XO. ... *.*",
+	"XO* X.. o.*",
+	".O. X.. ...",
+	// Cut.
+	"XO* O.x *x*",
+	"XO* O.X ***",
+	"*X* O.O xxx",
+	"OX* x.O ***",
+	// Edge.
+	"X.* O.* ###",
+	"OX* X.O ###",
+	"*X* o.O ###",
+	"*XO o.o ###",
+	"*OX X.O ###",
+}
+
+/*
+A Policy chooses the moves of a heavy playout. The rules are tried in order,
+each with the probability of its weight from 0 to 1, then a random move is
+played as in a light playout.
+
+	Escape    save a chain of the player in atari next to the last move
+	Capture   capture any opponent chain in atari
+	Pattern   play next to the last move where Patterns has a weight, never
+	          a self-atari
+*/
+type Policy struct {
+	Escape  float64
+	Capture float64
+	Pattern float64
+
+	// Probability of rejecting a random move which is a self-atari.
+	SelfAtari float64
+
+	// Weights of 3x3 codes with the player to move black, zero for no move.
+	Patterns *hash.Table3x3
+}
+
+// NewPolicy create a Policy object with every rule on and the MoGo patterns.
+func NewPolicy() *Policy {
+
+	t := hash.NewTable3x3()
+
+	for code := 0; code < hash.NumCodes3x3; code++ {
+		t.SetWeight(uint16(code), 0)
+	}
+
+	for _, s := range mogoPatterns {
+
+		// The patterns are known to compile.
+		m, _ := hash.CompileMatchPattern(strings.ReplaceAll(s, " ", "\n"))
+		t.SetMatchWeight(&m, 1)
+
+		m, _ = hash.CompileMatchPattern(strings.ReplaceAll(swapDiagram(s), " ", "\n"))
+		t.SetMatchWeight(&m, 1)
+	}
+
+	return &Policy{
+		Escape:    1,
+		Capture:   1,
+		Pattern:   1,
+		SelfAtari: 1,
+		Patterns:  t,
+	}
+}
+
+// swapDiagram exchanges black and white in a pattern diagram.
+func swapDiagram(s string) string {
+
+	return strings.Map(func(r rune) rune {
+
+		switch r {
+		case 'X':
+			return 'O'
+		case 'O':
+			return 'X'
+		case 'x':
+			return 'o'
+		case 'o':
+			return 'x'
+		}
+
+		return r
+	}, s)
+}
+
+// HeavyPlayout plays moves chosen by a policy until both players pass,
+// never filling own eyes, starting with the player to move. It returns the
+// number of moves played, to be taken back with Undo.
+func (bd *Board) HeavyPlayout(p *Policy, rnd *rand.Rand) int {
+
+	clr := bd.toPlay()
+
+	last := bd.lastMove()
+
+	maxMoves := 3 * bd.size * bd.size
+
+	moves := 0
+	passes := 0
+
+	candidates := make([]int, 0, bd.size*bd.size)
+
+	for passes < 2 && moves < maxMoves && bd.depth < bd.maxHistory {
+
+		last = bd.playHeavy(p, clr, last, rnd, &candidates)
+
+		if last != 0 {
+			moves++
+			passes = 0
+		} else {
+			passes++
+		}
+
+		clr = bd.oppositePlayer(clr)
+	}
+
+	return moves
+}
+
+// playHeavy plays a move of clr chosen by a policy after the opponent's move
+// on last, 0 after a pass. It returns the point played, 0 if there is none.
+func (bd *Board) playHeavy(p *Policy, clr state, last int, rnd *rand.Rand, candidates *[]int) int {
+
+	if last != 0 {
+
+		rules := []struct {
+			weight float64
+			moves  func(clr state, last int, cs []int) []int
+		}{
+			{p.Escape, bd.escapeMoves},
+			{p.Capture, bd.captureMoves},
+		}
+
+		for _, r := range rules {
+
+			if r.weight <= 0 || rnd.Float64() >= r.weight {
+				continue
+			}
+
+			cs := bd.playable(r.moves(clr, last, (*candidates)[:0]), clr)
+
+			if len(cs) > 0 {
+
+				pt := cs[rnd.Intn(len(cs))]
+				bd.do(pt, clr)
+
+				return pt
+			}
+		}
+
+		if p.Pattern > 0 && rnd.Float64() < p.Pattern {
+			if pt := bd.patternMove(p, clr, last, rnd); pt != 0 {
+
+				bd.do(pt, clr)
+
+				return pt
+			}
+		}
+	}
+
+	return bd.playRandom(clr, p.SelfAtari, rnd, candidates)
+}
+
+// escapeMoves appends the moves saving chains of clr in atari next to last:
+// extending to two liberties or more, or capturing a neighbor in atari.
+func (bd *Board) escapeMoves(clr state, last int, cs []int) []int {
+
+	for _, n := range bd.neighbors(last) {
+
+		if bd.states[n] != clr || bd.chains[n].numLiberties != 1 {
+			continue
+		}
+
+		c := bd.chains[n]
+
+		if l := c.liberties[0]; bd.predict(l, clr).Liberties >= 2 {
+			cs = append(cs, l)
+		}
+
+		for _, s := range c.points[:c.numPoints] {
+			for _, m := range bd.neighbors(s) {
+
+				if bd.states[m] == bd.oppositePlayer(clr) && bd.chains[m].numLiberties == 1 {
+					cs = append(cs, bd.chains[m].liberties[0])
+				}
+			}
+		}
+	}
+
+	return cs
+}
+
+// captureMoves appends the liberties of every opponent chain in atari,
+// wherever the last move was.
+func (bd *Board) captureMoves(clr state, _ int, cs []int) []int {
+
+	for y := 0; y < bd.size; y++ {
+		for x := 0; x < bd.size; x++ {
+
+			pt := bd.Point(x, y)
+
+			// Each chain once, at its representative.
+			if bd.states[pt] == bd.oppositePlayer(clr) && bd.chainReps[pt] == pt && bd.chains[pt].numLiberties == 1 {
+				cs = append(cs, bd.chains[pt].liberties[0])
+			}
+		}
+	}
+
+	return cs
+}
+
+// patternMove returns a point next to last chosen by pattern weight, 0 if no
+// pattern matches.
+func (bd *Board) patternMove(p *Policy, clr state, last int, rnd *rand.Rand) int {
+
+	var pts [8]int
+	var weights [8]float64
+
+	n := 0
+	total := 0.0
+
+	for _, pt := range bd.neighbors3x3(last) {
+
+		if bd.states[pt] != empty || bd.isEye(pt, clr) || bd.isLegal(pt, clr) != nil {
+			continue
+		}
+
+		code := bd.codes[pt]
+		if clr == white {
+			code = hash.SwapColors3x3(code)
+		}
+
+		w := p.Patterns.Weight(code)
+		if w <= 0 || bd.predict(pt, clr).SelfAtari {
+			continue
+		}
+
+		pts[n] = pt
+		weights[n] = w
+		total += w
+		n++
+	}
+
+	if n == 0 {
+		return 0
+	}
+
+	r := rnd.Float64() * total
+
+	for i := 0; i < n-1; i++ {
+
+		if r < weights[i] {
+			return pts[i]
+		}
+
+		r -= weights[i]
+	}
+
+	return pts[n-1]
+}
+
+// playable keeps the legal moves of clr which do not fill its eyes.
+func (bd *Board) playable(cs []int, clr state) []int {
+
+	r := cs[:0]
+
+	for _, pt := range cs {
+		if !bd.isEye(pt, clr) && bd.isLegal(pt, clr) == nil {
+			r = append(r, pt)
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,160 @@
+package board
+
+import (
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/hash"
+)
+
+func TestNewPolicy(t *testing.T) {
+
+	p := NewPolicy()
+
+	// Hane, the move at the centre.
+	hane := hash.NewPattern(3)
+	hane.SetBlack(0, 0)
+	hane.SetWhite(1, 0)
+	hane.SetBlack(2, 0)
+
+	if w := p.Patterns.Weight(hash.Code3x3(hane)); w != 1 {
+		t.Errorf("hane weight %v, expected 1", w)
+	}
+
+	if w := p.Patterns.Weight(hash.Code3x3(hane.Reversed())); w != 1 {
+		t.Errorf("reversed hane weight %v, expected 1", w)
+	}
+
+	// Hane on the first line, the cells MoGo does not care about off the
+	// board.
+	bh := NewBoard(9)
+	bh.DoBlack(bh.Point(3, 1))
+	bh.DoWhite(bh.Point(4, 1))
+	bh.DoBlack(bh.Point(5, 1))
+
+	m, err := hash.CompileMatchPattern(strings.ReplaceAll(mogoPatterns[0], " ", "\n"))
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if ok, _, _ := m.Match(bh.Pattern(bh.Point(4, 0), 3)); !ok {
+		t.Errorf("hane does not match on the first line")
+	}
+
+	if w := p.Patterns.Weight(bh.codes[bh.Point(4, 0)]); w != 1 {
+		t.Errorf("first line hane weight %v, expected 1", w)
+	}
+
+	if w := p.Patterns.Weight(0); w != 0 {
+		t.Errorf("empty weight %v, expected 0", w)
+	}
+}
+
+func TestCaptureMoves(t *testing.T) {
+
+	bh := NewBoard(9)
+
+	// A white stone in atari in the corner, the last move far away.
+	bh.DoWhite(bh.Point(0, 0))
+	bh.DoBlack(bh.Point(1, 0))
+	bh.DoWhite(bh.Point(4, 4))
+	bh.DoBlack(bh.Point(7, 7))
+	bh.DoWhite(bh.Point(5, 5))
+
+	cs := bh.captureMoves(black, bh.Point(5, 5), nil)
+
+	if len(cs) != 1 || cs[0] != bh.Point(0, 1) {
+		t.Errorf("capture moves %v, expected [%d]", cs, bh.Point(0, 1))
+	}
+
+	if cs := bh.captureMoves(white, bh.Point(7, 7), nil); len(cs) != 0 {
+		t.Errorf("white capture moves %v, expected none", cs)
+	}
+}
+
+func TestHeavyPlayoutUndo(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(2))
+	p := NewPolicy()
+
+	bh := NewBoard(9)
+	bh.DoBlack(bh.Point(4, 4))
+
+	before := bh.String()
+	h := bh.Hash()
+
+	moves := bh.HeavyPlayout(p, rnd)
+	if moves == 0 {
+		t.Fatal("no moves played")
+	}
+
+	for ; moves > 0; moves-- {
+		bh.Undo()
+	}
+
+	if bh.String() != before || bh.Hash() != h {
+		t.Errorf("board not restored\n%s", bh.String())
+	}
+}
+
+// TestTournament plays the heavy policy against the light one on 9x9, each
+// move chosen as in a playout, alternating colors.
+func TestTournament(t *testing.T) {
+
+	const (
+		games = 100
+		komi  = 7.5
+	)
+
+	p := NewPolicy()
+
+	wins := 0
+
+	for g := 0; g < games; g++ {
+
+		rnd := rand.New(rand.NewSource(int64(g)))
+
+		bh := NewBoard(9)
+
+		heavy := black
+		if g%2 == 1 {
+			heavy = white
+		}
+
+		candidates := make([]int, 0, 81)
+
+		clr := black
+		last := 0
+		passes := 0
+
+		for moves := 0; passes < 2 && moves < 3*81 && bh.depth < bh.maxHistory; moves++ {
+
+			if clr == heavy {
+				last = bh.playHeavy(p, clr, last, rnd, &candidates)
+			} else {
+				last = bh.playRandom(clr, 0, rnd, &candidates)
+			}
+
+			if last != 0 {
+				passes = 0
+			} else {
+				passes++
+			}
+
+			clr = bh.oppositePlayer(clr)
+		}
+
+		score := float64(bh.AreaScore()) - komi
+
+		if score > 0 == (heavy == black) {
+			wins++
+		}
+	}
+
+	if wins < games*3/4 {
+		t.Errorf("heavy policy won %d of %d games", wins, games)
+	}
+
+	t.Logf("heavy policy won %d of %d games", wins, games)
+}
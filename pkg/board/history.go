@@ -2,10 +2,13 @@ package board
 
 type history struct {
 
-	// Data to be recorded.
+	// Data to be recorded. point is 0 for a pass.
 	color state
 	point int
 
+	// Stone of the initial position rather than a move.
+	setup bool
+
 	// Ko point before move was played
 	koPoint int
 
@@ -0,0 +1,170 @@
+package board
+
+import (
+	"math/rand"
+)
+
+// Bouzy returns a territory estimate using Bouzy's 5/21 dilation and erosion
+// algorithm. The result is indexed as r[x][y]. Positive values are black
+// influence, negative values are white influence and zero is neutral.
+func (bd *Board) Bouzy() [][]float64 {
+
+	return bd.BouzyN(5, 21)
+}
+
+// BouzyN is Bouzy with a custom number of dilations and erosions.
+func (bd *Board) BouzyN(dilations, erosions int) [][]float64 {
+
+	v := make([]int, bd.boardSize)
+
+	for pt, s := range bd.states {
+
+		switch s {
+		case black:
+			v[pt] = 128
+		case white:
+			v[pt] = -128
+		}
+	}
+
+	for i := 0; i < dilations; i++ {
+		v = bd.dilate(v)
+	}
+
+	for i := 0; i < erosions; i++ {
+		v = bd.erode(v)
+	}
+
+	return bd.grid(func(pt int) float64 {
+		return float64(v[pt])
+	})
+}
+
+func (bd *Board) dilate(v []int) []int {
+
+	r := make([]int, len(v))
+	copy(r, v)
+
+	for pt, s := range bd.states {
+
+		if s == wall {
+			continue
+		}
+
+		nb := bd.neighbors(pt)
+
+		positive := 0
+		negative := 0
+
+		for i := 0; i < 4; i++ {
+
+			n := nb[i]
+
+			if bd.states[n] == wall {
+				continue
+			}
+
+			if v[n] > 0 {
+				positive++
+			} else if v[n] < 0 {
+				negative++
+			}
+		}
+
+		if v[pt] >= 0 && negative == 0 {
+			r[pt] += positive
+		}
+
+		if v[pt] <= 0 && positive == 0 {
+			r[pt] -= negative
+		}
+	}
+
+	return r
+}
+
+func (bd *Board) erode(v []int) []int {
+
+	r := make([]int, len(v))
+	copy(r, v)
+
+	for pt, s := range bd.states {
+
+		if s == wall || v[pt] == 0 {
+			continue
+		}
+
+		nb := bd.neighbors(pt)
+
+		for i := 0; i < 4; i++ {
+
+			n := nb[i]
+
+			if bd.states[n] == wall {
+				continue
+			}
+
+			if v[pt] > 0 && v[n] <= 0 {
+				r[pt]--
+			} else if v[pt] < 0 && v[n] >= 0 {
+				r[pt]++
+			}
+		}
+
+		// Erosion never crosses zero.
+		if v[pt] > 0 && r[pt] < 0 || v[pt] < 0 && r[pt] > 0 {
+			r[pt] = 0
+		}
+	}
+
+	return r
+}
+
+// Ownership returns the probability that black owns each point at the end
+// of the game, estimated by Monte Carlo playouts from the current position.
+// The result is indexed as r[x][y]. The board is restored before returning.
+func (bd *Board) Ownership(playouts int, rnd *rand.Rand) [][]float64 {
+
+	counts := make([]int, bd.boardSize)
+
+	for i := 0; i < playouts; i++ {
+
+		moves := bd.playout(rnd)
+
+		for pt, s := range bd.areaOwners() {
+			if s == black {
+				counts[pt]++
+			}
+		}
+
+		for j := 0; j < moves; j++ {
+			bd.Undo()
+		}
+	}
+
+	return bd.grid(func(pt int) float64 {
+
+		if playouts == 0 {
+			return 0
+		}
+
+		return float64(counts[pt]) / float64(playouts)
+	})
+}
+
+// grid maps a function of board indices to a size by size grid indexed as r[x][y].
+func (bd *Board) grid(f func(pt int) float64) [][]float64 {
+
+	r := make([][]float64, bd.size)
+
+	for x := 0; x < bd.size; x++ {
+
+		r[x] = make([]float64, bd.size)
+
+		for y := 0; y < bd.size; y++ {
+			r[x][y] = f(bd.Point(x, y))
+		}
+	}
+
+	return r
+}
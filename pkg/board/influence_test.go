@@ -0,0 +1,103 @@
+package board
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPointXY(t *testing.T) {
+
+	bh := NewBoard(7)
+
+	for x := 0; x < 7; x++ {
+		for y := 0; y < 7; y++ {
+
+			pt := bh.Point(x, y)
+
+			if bh.states[pt] != empty {
+				t.Errorf("(%d, %d) -> %d is not on the board", x, y, pt)
+			}
+
+			if px, py := bh.XY(pt); px != x || py != y {
+				t.Errorf("(%d, %d) -> %d -> (%d, %d)", x, y, pt, px, py)
+			}
+		}
+	}
+}
+
+func TestBouzy(t *testing.T) {
+
+	bh := NewBoard(9)
+
+	bh.DoBlack(bh.Point(2, 2))
+	bh.DoWhite(bh.Point(6, 6))
+
+	r := bh.Bouzy()
+
+	if len(r) != 9 || len(r[0]) != 9 {
+		t.Fatalf("grid is %dx%d, expected 9x9", len(r), len(r[0]))
+	}
+
+	if r[1][1] <= 0 {
+		t.Errorf("(1, 1) = %v, expected black influence", r[1][1])
+	}
+
+	if r[7][7] >= 0 {
+		t.Errorf("(7, 7) = %v, expected white influence", r[7][7])
+	}
+
+	if r[4][4] != 0 {
+		t.Errorf("(4, 4) = %v, expected neutral", r[4][4])
+	}
+}
+
+func TestOwnership(t *testing.T) {
+
+	bh := NewBoard(5)
+
+	// Black wall on the third column, white wall on the fourth.
+	for y := 0; y < 5; y++ {
+		bh.DoBlack(bh.Point(2, y))
+		bh.DoWhite(bh.Point(3, y))
+	}
+
+	before := bh.String()
+
+	r := bh.Ownership(50, rand.New(rand.NewSource(1)))
+
+	if after := bh.String(); after != before {
+		t.Errorf("board changed\n before\n%s\n after\n%s", before, after)
+	}
+
+	if r[0][0] < 0.5 {
+		t.Errorf("(0, 0) = %v, expected mostly black", r[0][0])
+	}
+
+	if r[4][4] > 0.5 {
+		t.Errorf("(4, 4) = %v, expected mostly white", r[4][4])
+	}
+}
+
+func TestPlayoutUndo(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(7))
+
+	for i := 0; i < 20; i++ {
+
+		bh := NewBoard(9)
+
+		before := bh.String()
+
+		moves := bh.playout(rnd)
+
+		for j := 0; j < moves; j++ {
+			if err := bh.Undo(); err != nil {
+				t.Fatal(err.Error())
+			}
+		}
+
+		if after := bh.String(); after != before {
+			t.Fatalf("playout %d not undone\n%s", i, after)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package board
+
+import (
+	"github.com/gosharplite/goxit/pkg/hash"
+)
+
+// Pattern returns the size by size square centred on a point. Points off
+// the board are edges, set both black and white. Size is at most
+// hash.MaxSize, Pattern panics otherwise.
+func (bd *Board) Pattern(pt, size int) hash.Pattern {
+
+	p := hash.NewPattern(size)
+
+	cx, cy := bd.XY(pt)
+	half := size / 2
+
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+
+			x := cx + i - half
+			y := cy + j - half
+
+			if x < 0 || y < 0 || x >= bd.size || y >= bd.size {
+				p.SetBlack(i, j)
+				p.SetWhite(i, j)
+				continue
+			}
+
+			switch bd.states[bd.Point(x, y)] {
+			case black:
+				p.SetBlack(i, j)
+			case white:
+				p.SetWhite(i, j)
+			}
+		}
+	}
+
+	return p
+}
+
+// ShapedPattern returns the cells of a shape whose box is centred on a point.
+// Points off the board are edges. Shapes have no size limit.
+func (bd *Board) ShapedPattern(pt int, s *hash.Shape) hash.ShapedPattern {
+
+	p := hash.NewShapedPattern(s)
+
+	cx, cy := bd.XY(pt)
+
+	for i := 0; i < s.Width(); i++ {
+		for j := 0; j < s.Height(); j++ {
+
+			if !s.Has(i, j) {
+				continue
+			}
+
+			x := cx + i - (s.Width()-1)/2
+			y := cy + j - (s.Height()-1)/2
+
+			if x < 0 || y < 0 || x >= bd.size || y >= bd.size {
+				p.SetEdge(i, j)
+				continue
+			}
+
+			switch bd.states[bd.Point(x, y)] {
+			case black:
+				p.SetBlack(i, j)
+			case white:
+				p.SetWhite(i, j)
+			}
+		}
+	}
+
+	return p
+}
@@ -0,0 +1,234 @@
+package board
+
+import (
+	"math/rand"
+)
+
+// toPlay returns the color of the player whose turn it is.
+func (bd *Board) toPlay() state {
+
+	if bd.depth == 0 {
+		return black
+	}
+
+	return bd.oppositePlayer(bd.histories[bd.depth].color)
+}
+
+// isEye reports whether pt is an empty point surrounded by clr which
+// should not be filled during a playout.
+func (bd *Board) isEye(pt int, clr state) bool {
+
+	if bd.states[pt] != empty {
+		return false
+	}
+
+	nb := bd.neighbors(pt)
+
+	for i := 0; i < 4; i++ {
+
+		s := bd.states[nb[i]]
+
+		if s != clr && s != wall {
+			return false
+		}
+	}
+
+	// Count enemy diagonals to rule out false eyes.
+	walls := 0
+	enemies := 0
+
+	for _, d := range bd.diagonals(pt) {
+
+		switch bd.states[d] {
+		case wall:
+			walls++
+		case bd.oppositePlayer(clr):
+			enemies++
+		}
+	}
+
+	if walls > 0 {
+		return enemies == 0
+	}
+
+	return enemies < 2
+}
+
+// playout plays uniformly random moves, never filling own eyes, until both
+// players pass. It returns the number of moves played so the caller can Undo
+// them.
+func (bd *Board) playout(rnd *rand.Rand) int {
+
+	clr := bd.toPlay()
+
+	maxMoves := 3 * bd.size * bd.size
+
+	moves := 0
+	passes := 0
+
+	candidates := make([]int, 0, bd.size*bd.size)
+
+	for passes < 2 && moves < maxMoves && bd.depth < bd.maxHistory {
+
+		if bd.playRandom(clr, 0, rnd, &candidates) != 0 {
+			moves++
+			passes = 0
+		} else {
+			passes++
+		}
+
+		clr = bd.oppositePlayer(clr)
+	}
+
+	return moves
+}
+
+// playRandom plays a uniformly random move of clr which does not fill its
+// eyes, rejecting self-atari with probability selfAtari. It returns the point
+// played, 0 if there is none. candidates is a buffer for the empty points.
+func (bd *Board) playRandom(clr state, selfAtari float64, rnd *rand.Rand, candidates *[]int) int {
+
+	cs := (*candidates)[:0]
+
+	for pt := range bd.states {
+		if bd.states[pt] == empty {
+			cs = append(cs, pt)
+		}
+	}
+
+	*candidates = cs
+
+	for len(cs) > 0 {
+
+		i := rnd.Intn(len(cs))
+		pt := cs[i]
+
+		// remove pt
+		cs[i] = cs[len(cs)-1]
+		cs = cs[:len(cs)-1]
+
+		if bd.isEye(pt, clr) {
+			continue
+		}
+
+		if selfAtari > 0 && bd.predict(pt, clr).SelfAtari && rnd.Float64() < selfAtari {
+			continue
+		}
+
+		if bd.do(pt, clr) == nil {
+			return pt
+		}
+	}
+
+	return 0
+}
+
+// areaOwners returns, for every index, the color owning it under area
+// scoring. Empty regions bordered by both colors stay empty.
+func (bd *Board) areaOwners() []state {
+
+	owners := make([]state, bd.boardSize)
+	visited := make([]bool, bd.boardSize)
+
+	for pt, s := range bd.states {
+
+		if s != empty {
+			owners[pt] = s
+			continue
+		}
+
+		if visited[pt] {
+			continue
+		}
+
+		// Flood fill the empty region.
+		region := []int{pt}
+		visited[pt] = true
+
+		reachesBlack := false
+		reachesWhite := false
+
+		for i := 0; i < len(region); i++ {
+
+			nb := bd.neighbors(region[i])
+
+			for j := 0; j < 4; j++ {
+
+				n := nb[j]
+
+				switch bd.states[n] {
+				case black:
+					reachesBlack = true
+				case white:
+					reachesWhite = true
+				case empty:
+					if !visited[n] {
+						visited[n] = true
+						region = append(region, n)
+					}
+				}
+			}
+		}
+
+		owner := empty
+
+		if reachesBlack && !reachesWhite {
+			owner = black
+		} else if reachesWhite && !reachesBlack {
+			owner = white
+		}
+
+		for _, r := range region {
+			owners[r] = owner
+		}
+	}
+
+	return owners
+}
+
+// diagonals returns diagonal points with order north-east/south-east/south-west/north-west.
+func (bd *Board) diagonals(pt int) []int {
+
+	return []int{
+		pt - bd.size,
+		pt + (bd.size + 2),
+		pt + bd.size,
+		pt - (bd.size + 2)}
+}
+
+// Playout plays random moves until both players pass, never filling own
+// eyes, starting with the player to move. It returns the number of moves
+// played, to be taken back with Undo.
+func (bd *Board) Playout(rnd *rand.Rand) int {
+
+	return bd.playout(rnd)
+}
+
+// IsEye reports whether a point is an empty point surrounded by a color
+// which a playout would not fill.
+func (bd *Board) IsEye(pt int, isBlack bool) bool {
+
+	if isBlack {
+		return bd.isEye(pt, black)
+	}
+
+	return bd.isEye(pt, white)
+}
+
+// AreaScore returns the points owned by black minus those owned by white
+// under area scoring, without komi.
+func (bd *Board) AreaScore() int {
+
+	r := 0
+
+	for _, s := range bd.areaOwners() {
+		switch s {
+		case black:
+			r++
+		case white:
+			r--
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,129 @@
+package board
+
+// IsBlack reports whether a black stone is on a point.
+func (bd *Board) IsBlack(pt int) bool {
+
+	return bd.states[pt] == black
+}
+
+// IsWhite reports whether a white stone is on a point.
+func (bd *Board) IsWhite(pt int) bool {
+
+	return bd.states[pt] == white
+}
+
+// IsEmpty reports whether a point is on the board and has no stone.
+func (bd *Board) IsEmpty(pt int) bool {
+
+	return bd.isEmpty(pt)
+}
+
+// IsLegalBlack returns nil if black can play on a point.
+func (bd *Board) IsLegalBlack(pt int) error {
+
+	return bd.isLegal(pt, black)
+}
+
+// IsLegalWhite returns nil if white can play on a point.
+func (bd *Board) IsLegalWhite(pt int) error {
+
+	return bd.isLegal(pt, white)
+}
+
+// BlackToPlay reports whether black moves next. Black moves first and
+// colors alternate after the last move, pass or setup stone, so white moves
+// after black handicap stones.
+func (bd *Board) BlackToPlay() bool {
+
+	return bd.toPlay() == black
+}
+
+// Neighbors returns surrounding points with order north/east/south/west.
+// Points off the board are walls.
+func (bd *Board) Neighbors(pt int) []int {
+
+	return bd.neighbors(pt)
+}
+
+// Chain returns the points of the chain on a point, nil if there is no stone.
+func (bd *Board) Chain(pt int) []int {
+
+	c := bd.chains[pt]
+	if c == nil {
+		return nil
+	}
+
+	r := make([]int, c.numPoints)
+	copy(r, c.points[:c.numPoints])
+
+	return r
+}
+
+// Liberties returns the liberties of the chain on a point, nil if there is no stone.
+func (bd *Board) Liberties(pt int) []int {
+
+	c := bd.chains[pt]
+	if c == nil {
+		return nil
+	}
+
+	r := make([]int, c.numLiberties)
+	copy(r, c.liberties[:c.numLiberties])
+
+	return r
+}
+
+// NumLiberties returns the number of liberties of the chain on a point.
+func (bd *Board) NumLiberties(pt int) int {
+
+	c := bd.chains[pt]
+	if c == nil {
+		return 0
+	}
+
+	return c.numLiberties
+}
+
+// KoPoint returns the current ko point if exists, 0 otherwise.
+func (bd *Board) KoPoint() int {
+
+	return bd.koPoint
+}
+
+// Prisoners returns the number of stones captured by black and by white.
+func (bd *Board) Prisoners() (byBlack, byWhite int) {
+
+	return bd.blackDead, bd.whiteDead
+}
+
+// Depth returns the number of moves, passes and setup stones played.
+func (bd *Board) Depth() int {
+
+	return bd.depth
+}
+
+// Move returns the point and color of the n-th move, counting from 1. The
+// point is 0 for a pass.
+func (bd *Board) Move(n int) (pt int, isBlack bool) {
+
+	h := bd.histories[n]
+
+	return h.point, h.color == black
+}
+
+// IsSetup reports whether the n-th move is a setup stone.
+func (bd *Board) IsSetup(n int) bool {
+
+	return bd.histories[n].setup
+}
+
+// lastMove returns the point of the last move, 0 for a pass, a setup stone
+// or no move.
+func (bd *Board) lastMove() int {
+
+	if bd.depth == 0 || bd.histories[bd.depth].setup {
+		return 0
+	}
+
+	return bd.histories[bd.depth].point
+}
@@ -0,0 +1,145 @@
+package board
+
+// A MoveInfo predicts the result of a move.
+type MoveInfo struct {
+
+	// Number of stones captured.
+	Captures int
+
+	// Liberties of the chain of the played stone, zero for suicide.
+	Liberties int
+
+	// The chain of the played stone has one liberty.
+	SelfAtari bool
+}
+
+// Predict returns the result of a color playing on a point without changing
+// the board. Legality is not checked, an occupied point gives a zero
+// MoveInfo.
+func (bd *Board) Predict(pt int, isBlack bool) MoveInfo {
+
+	clr := white
+	if isBlack {
+		clr = black
+	}
+
+	return bd.predict(pt, clr)
+}
+
+// IsSelfAtari reports whether a color playing on a point leaves its chain with
+// one liberty.
+func (bd *Board) IsSelfAtari(pt int, isBlack bool) bool {
+
+	return bd.Predict(pt, isBlack).SelfAtari
+}
+
+func (bd *Board) predict(pt int, clr state) MoveInfo {
+
+	var r MoveInfo
+
+	if !bd.isEmpty(pt) {
+		return r
+	}
+
+	ns := bd.neighbors(pt)
+
+	// Distinct neighboring chains joined and captured by the move.
+	var friends, captured [4]*chain
+	nf, nc := 0, 0
+
+	for _, n := range ns {
+
+		c := bd.chains[n]
+
+		switch {
+		case bd.states[n] == empty:
+			r.Liberties++
+		case bd.states[n] == clr && !containsChain(friends[:nf], c):
+			friends[nf] = c
+			nf++
+		case bd.states[n] == bd.oppositePlayer(clr) && c.numLiberties == 1 && !containsChain(captured[:nc], c):
+			captured[nc] = c
+			nc++
+		}
+	}
+
+	// Liberties of joined chains, other than the point and its empty
+	// neighbors, counted once.
+	for i, f := range friends[:nf] {
+		for _, l := range f.liberties[:f.numLiberties] {
+
+			if l == pt || isNeighbor(ns, l) {
+				continue
+			}
+
+			seen := false
+			for _, g := range friends[:i] {
+				if g.libertiesIndices[l] != -1 {
+					seen = true
+					break
+				}
+			}
+
+			if !seen {
+				r.Liberties++
+			}
+		}
+	}
+
+	// Captured stones next to the new chain become liberties.
+	for _, c := range captured[:nc] {
+
+		r.Captures += c.numPoints
+
+		for _, s := range c.points[:c.numPoints] {
+			if bd.touches(s, pt, friends[:nf]) {
+				r.Liberties++
+			}
+		}
+	}
+
+	r.SelfAtari = r.Liberties == 1
+
+	return r
+}
+
+// touches reports whether a point is next to pt or to a stone of chains.
+func (bd *Board) touches(s, pt int, chains []*chain) bool {
+
+	for _, n := range bd.neighbors(s) {
+
+		if n == pt {
+			return true
+		}
+
+		for _, c := range chains {
+			if c.hasPoint(n) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func containsChain(cs []*chain, c *chain) bool {
+
+	for _, x := range cs {
+		if x == c {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isNeighbor(ns []int, pt int) bool {
+
+	for _, n := range ns {
+		if n == pt {
+			return true
+		}
+	}
+
+	return false
+}
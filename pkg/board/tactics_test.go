@@ -0,0 +1,142 @@
+package board
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestPredict(t *testing.T) {
+
+	cases := map[string]struct {
+		black, white [][2]int
+		move         [2]int
+		isBlack      bool
+		expected     MoveInfo
+	}{
+		"empty board": {
+			move:     [2]int{3, 3},
+			isBlack:  true,
+			expected: MoveInfo{Liberties: 4},
+		},
+		"corner self-atari": {
+			white:    [][2]int{{1, 0}},
+			move:     [2]int{0, 0},
+			isBlack:  true,
+			expected: MoveInfo{Liberties: 1, SelfAtari: true},
+		},
+		"suicide": {
+			white:    [][2]int{{1, 0}, {0, 1}},
+			move:     [2]int{0, 0},
+			isBlack:  true,
+			expected: MoveInfo{},
+		},
+		"capture three": {
+			black:    [][2]int{{2, 0}, {2, 1}, {0, 2}, {1, 2}},
+			white:    [][2]int{{0, 1}, {1, 1}, {1, 0}},
+			move:     [2]int{0, 0},
+			isBlack:  true,
+			expected: MoveInfo{Captures: 3, Liberties: 2},
+		},
+		"join shares liberties": {
+			black:    [][2]int{{2, 1}, {4, 1}},
+			move:     [2]int{3, 1},
+			isBlack:  true,
+			expected: MoveInfo{Liberties: 8},
+		},
+		"occupied": {
+			white:    [][2]int{{3, 3}},
+			move:     [2]int{3, 3},
+			isBlack:  true,
+			expected: MoveInfo{},
+		},
+	}
+
+	for k, tc := range cases {
+
+		bh := NewBoard(7)
+
+		for _, p := range tc.black {
+			bh.DoBlack(bh.Point(p[0], p[1]))
+		}
+
+		for _, p := range tc.white {
+			bh.DoWhite(bh.Point(p[0], p[1]))
+		}
+
+		before := bh.String()
+
+		info := bh.Predict(bh.Point(tc.move[0], tc.move[1]), tc.isBlack)
+
+		if info != tc.expected {
+			t.Errorf("%s: %+v, expected %+v", k, info, tc.expected)
+		}
+
+		if bh.String() != before {
+			t.Errorf("%s: board changed", k)
+		}
+	}
+}
+
+// TestPredictPlayed checks predictions against playing every move of random
+// positions.
+func TestPredictPlayed(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(3))
+
+	for i := 0; i < 20; i++ {
+
+		bh := NewBoard(9)
+
+		// Stop part way so positions have chains in atari.
+		for j := 0; j < 20+rnd.Intn(60); j++ {
+
+			pt := bh.Point(rnd.Intn(9), rnd.Intn(9))
+
+			if j%2 == 0 {
+				bh.DoBlack(pt)
+			} else {
+				bh.DoWhite(pt)
+			}
+		}
+
+		for pt := 0; pt < bh.boardSize; pt++ {
+
+			if !bh.IsEmpty(pt) {
+				continue
+			}
+
+			for _, isBlack := range []bool{true, false} {
+
+				info := bh.Predict(pt, isBlack)
+
+				byBlack, byWhite := bh.Prisoners()
+
+				var err error
+				if isBlack {
+					err = bh.DoBlack(pt)
+				} else {
+					err = bh.DoWhite(pt)
+				}
+
+				if err != nil {
+					if bh.isSuicide(pt, black) && isBlack || bh.isSuicide(pt, white) && !isBlack {
+						if info.Liberties != 0 {
+							t.Errorf("suicide at %d has %d liberties\n%s", pt, info.Liberties, bh.String())
+						}
+					}
+					continue
+				}
+
+				b, w := bh.Prisoners()
+
+				captures := b - byBlack + w - byWhite
+
+				if info.Captures != captures || info.Liberties != bh.NumLiberties(pt) {
+					t.Errorf("move at %d: %+v, played %d captures and %d liberties", pt, info, captures, bh.NumLiberties(pt))
+				}
+
+				bh.Undo()
+			}
+		}
+	}
+}
@@ -0,0 +1,124 @@
+package board
+
+import (
+	"sync"
+
+	"github.com/gosharplite/goxit/pkg/hash"
+)
+
+const (
+	// WhiteToPlay is mixed into the keys of positions with white to move. It
+	// must not change as stored keys depend on it.
+	WhiteToPlay = 0x2545f4914f6cdd1d
+
+	// koKey is multiplied by the ko point in Key.
+	koKey = 0x9e3779b97f4a7c15
+)
+
+// zobristTables holds the key table of each board size, shared by boards and
+// never modified once built.
+var zobristTables = struct {
+	sync.Mutex
+	keys map[int][]uint64
+}{keys: map[int][]uint64{}}
+
+// initZobristKeys finds the keys of a stone seen through each symmetry,
+// building them on the first board of a size. Index is pt*16 + sym*2 +
+// color, color 0 for black and 1 for white.
+func (bd *Board) initZobristKeys() {
+
+	zobristTables.Lock()
+	defer zobristTables.Unlock()
+
+	if keys, ok := zobristTables.keys[bd.size]; ok {
+		bd.zobristKeys = keys
+		return
+	}
+
+	bd.zobristKeys = make([]uint64, bd.boardSize*16)
+
+	for y := 0; y < bd.size; y++ {
+		for x := 0; x < bd.size; x++ {
+
+			pt := bd.Point(x, y)
+
+			for s := hash.Symmetry(0); s < 8; s++ {
+
+				tx, ty := s.Apply(x, y, bd.size)
+				i := uint64(ty*bd.size + tx)
+
+				bd.zobristKeys[pt*16+int(s)*2] = hash.ZobristKey(uint64(bd.size)<<34 | i<<2 | 1)
+				bd.zobristKeys[pt*16+int(s)*2+1] = hash.ZobristKey(uint64(bd.size)<<34 | i<<2 | 2)
+			}
+		}
+	}
+
+	zobristTables.keys[bd.size] = bd.zobristKeys
+}
+
+// initZobrist computes the hashes of the stones on the board.
+func (bd *Board) initZobrist() {
+
+	for s := range bd.zobrist {
+		bd.zobrist[s] = hash.ZobristKey(uint64(bd.size) << 34)
+	}
+
+	for pt, s := range bd.states {
+		if s == black || s == white {
+			bd.xorStone(pt, s)
+		}
+	}
+}
+
+// xorStone adds or removes a stone from the hashes.
+func (bd *Board) xorStone(pt int, clr state) {
+
+	keys := bd.zobristKeys[pt*16 : pt*16+16]
+
+	for s := range bd.zobrist {
+		bd.zobrist[s] ^= keys[s*2+int(clr)]
+	}
+}
+
+// Hash returns the Zobrist hash of the stones on the board. It is kept up
+// to date by every move and Undo. The color to move, the ko point and the
+// prisoners are not included.
+func (bd *Board) Hash() uint64 {
+
+	return bd.zobrist[0]
+}
+
+// Key returns Hash with the color to move and the ko point mixed in, a key
+// for tables of positions.
+func (bd *Board) Key(isBlack bool) uint64 {
+
+	k := bd.zobrist[0] ^ uint64(bd.koPoint)*koKey
+
+	if !isBlack {
+		k ^= WhiteToPlay
+	}
+
+	return k
+}
+
+// SymmetricHashes returns the Hash of the board mapped by each
+// hash.Symmetry.
+func (bd *Board) SymmetricHashes() [8]uint64 {
+
+	return bd.zobrist
+}
+
+// CanonicalHash returns the smallest SymmetricHashes, equal for mirrored and
+// rotated positions.
+func (bd *Board) CanonicalHash() uint64 {
+
+	r := bd.zobrist[0]
+
+	for _, h := range bd.zobrist[1:] {
+		if h < r {
+			r = h
+		}
+	}
+
+	return r
+}
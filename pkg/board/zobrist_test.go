@@ -0,0 +1,152 @@
+package board
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/hash"
+)
+
+func TestZobrist(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(5))
+
+	bh := NewBoard(9)
+
+	empty := bh.Hash()
+
+	check := func(when string) {
+
+		expected := bh.zobrist
+		bh.initZobrist()
+
+		if bh.zobrist != expected {
+			t.Fatalf("%s: hashes %x, expected %x\n%s", when, expected, bh.zobrist, bh.String())
+		}
+	}
+
+	moves := bh.playout(rnd)
+
+	check("after playout")
+
+	for j := 0; j < moves; j++ {
+
+		bh.Undo()
+
+		check("after undo")
+	}
+
+	if bh.Hash() != empty {
+		t.Errorf("hash %#x after undoing every move, expected %#x", bh.Hash(), empty)
+	}
+}
+
+func TestZobristKeysShared(t *testing.T) {
+
+	a := NewBoard(9)
+	b := NewBoard(9)
+	c := NewBoard(7)
+
+	if &a.zobristKeys[0] != &b.zobristKeys[0] {
+		t.Error("boards of the same size build their own keys")
+	}
+
+	if len(c.zobristKeys) != c.boardSize*16 {
+		t.Errorf("%d keys for size 7, expected %d", len(c.zobristKeys), c.boardSize*16)
+	}
+}
+
+func TestKey(t *testing.T) {
+
+	a := NewBoard(5)
+	a.DoBlack(a.Point(1, 1))
+	a.DoWhite(a.Point(3, 3))
+	a.DoBlack(a.Point(1, 3))
+
+	b := NewBoard(5)
+	b.DoBlack(b.Point(1, 3))
+	b.DoWhite(b.Point(3, 3))
+	b.DoBlack(b.Point(1, 1))
+
+	if a.Key(false) != b.Key(false) {
+		t.Error("transposed move orders have different keys")
+	}
+
+	if a.Key(false) == a.Key(true) {
+		t.Error("colors to move have the same key")
+	}
+
+	// The same stones with and without a ko point.
+	c := NewBoard(3)
+	c.DoBlack(5)
+	c.DoBlack(7)
+	c.DoBlack(10)
+	c.DoWhite(9)
+	c.DoWhite(6)
+
+	d := NewBoard(3)
+	d.DoBlack(7)
+	d.DoBlack(10)
+	d.DoWhite(9)
+	d.DoWhite(6)
+
+	if c.KoPoint() == 0 || c.Hash() != d.Hash() || c.Key(true) == d.Key(true) {
+		t.Error("ko point not in the key")
+	}
+}
+
+func TestCanonicalHash(t *testing.T) {
+
+	moves := [][2]int{{2, 2}, {6, 2}, {3, 6}, {4, 4}, {2, 3}}
+
+	bh := NewBoard(9)
+
+	for i, m := range moves {
+		if i%2 == 0 {
+			bh.DoBlack(bh.Point(m[0], m[1]))
+		} else {
+			bh.DoWhite(bh.Point(m[0], m[1]))
+		}
+	}
+
+	hashes := bh.SymmetricHashes()
+
+	for s := hash.Symmetry(0); s < 8; s++ {
+
+		sh := NewBoard(9)
+
+		for i, m := range moves {
+
+			x, y := s.Apply(m[0], m[1], 9)
+
+			if i%2 == 0 {
+				sh.DoBlack(sh.Point(x, y))
+			} else {
+				sh.DoWhite(sh.Point(x, y))
+			}
+		}
+
+		if sh.Hash() != hashes[s] {
+			t.Errorf("symmetry %d: hash %#x, expected %#x", s, sh.Hash(), hashes[s])
+		}
+
+		if sh.CanonicalHash() != bh.CanonicalHash() {
+			t.Errorf("symmetry %d: canonical hash %#x, expected %#x", s, sh.CanonicalHash(), bh.CanonicalHash())
+		}
+	}
+
+	// Colors matter.
+	rh := NewBoard(9)
+
+	for i, m := range moves {
+		if i%2 == 0 {
+			rh.DoWhite(rh.Point(m[0], m[1]))
+		} else {
+			rh.DoBlack(rh.Point(m[0], m[1]))
+		}
+	}
+
+	if rh.CanonicalHash() == bh.CanonicalHash() {
+		t.Error("color reversed position has the same canonical hash")
+	}
+}
@@ -0,0 +1,365 @@
+/*
+Package book provides a library for an opening book built from game records.
+
+Positions are keyed by the symmetric canonical hash of the whole board and
+the color to move, so mirrored and rotated openings and different move
+orders reaching the same position share statistics. Moves are stored in the
+canonical orientation, with moves equivalent under a symmetry of the
+position merged.
+
+The file format is little endian and versioned.
+
+	magic     "GXOB"
+	version   uint16
+	count     uint32
+	positions count times, sorted by key
+	  key     uint64
+	  moves   uvarint
+	  moves times, sorted by point
+	    x, y   uint8
+	    games  uvarint
+	    wins   uvarint
+	    losses uvarint
+*/
+package book
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/hash"
+	"github.com/gosharplite/goxit/pkg/internal/countwriter"
+	"github.com/gosharplite/goxit/pkg/sgf"
+)
+
+const (
+	magic   = "GXOB"
+	version = 1
+)
+
+// errStop ends the replay of a game after the last book move.
+var errStop = errors.New("book: stop")
+
+// Stats counts the games a move was played in, from the view of the player
+// making the move. Games without a winner count as neither.
+type Stats struct {
+	Games  uint64
+	Wins   uint64
+	Losses uint64
+}
+
+// WinRate returns the fraction of games won, games without a winner counting
+// half.
+func (s Stats) WinRate() float64 {
+
+	if s.Games == 0 {
+		return 0
+	}
+
+	return (float64(s.Wins) + float64(s.Games-s.Wins-s.Losses)/2) / float64(s.Games)
+}
+
+// A Continuation is a book move with its statistics.
+type Continuation struct {
+	X, Y int
+
+	Stats
+}
+
+// A Book contains the moves played from opening positions.
+type Book struct {
+
+	// Number of moves of each game added to the book.
+	MaxMoves int
+
+	// Moves of each position key in canonical coordinates.
+	positions map[uint64][]Continuation
+}
+
+// New create a Book object learning the first maxMoves moves of games.
+func New(maxMoves int) *Book {
+
+	return &Book{
+		MaxMoves:  maxMoves,
+		positions: map[uint64][]Continuation{},
+	}
+}
+
+// Len returns the number of positions.
+func (b *Book) Len() int {
+
+	return len(b.positions)
+}
+
+// AddGame adds the opening of the main line of a game, until the first pass.
+// The winner is read from the RE property.
+func (b *Book) AddGame(root *sgf.Node) error {
+
+	if root.Size() > hash.MaxSize {
+		return fmt.Errorf("book: board size %d is larger than %d", root.Size(), hash.MaxSize)
+	}
+
+	re := root.Value("RE")
+
+	blackWon := strings.HasPrefix(re, "B+")
+	whiteWon := strings.HasPrefix(re, "W+")
+
+	n := 0
+
+	err := sgf.Replay(root, func(bd *board.Board, m sgf.Move) error {
+
+		if m.Pass || n >= b.MaxMoves {
+			return errStop
+		}
+
+		n++
+
+		key, c, sym := position(bd, m.Black)
+		x, y := canonicalMove(&c, sym, m.X, m.Y)
+
+		s := b.find(key, x, y)
+
+		s.Games++
+
+		switch {
+		case m.Black && blackWon || !m.Black && whiteWon:
+			s.Wins++
+		case m.Black && whiteWon || !m.Black && blackWon:
+			s.Losses++
+		}
+
+		return nil
+	})
+
+	if err == errStop {
+		return nil
+	}
+
+	return err
+}
+
+// find returns the statistics of a move, adding it if needed.
+func (b *Book) find(key uint64, x, y int) *Stats {
+
+	cs := b.positions[key]
+
+	for i := range cs {
+		if cs[i].X == x && cs[i].Y == y {
+			return &cs[i].Stats
+		}
+	}
+
+	b.positions[key] = append(cs, Continuation{X: x, Y: y})
+
+	cs = b.positions[key]
+
+	return &cs[len(cs)-1].Stats
+}
+
+// Lookup returns the book moves of a position for a color in board
+// coordinates, most played first.
+func (b *Book) Lookup(bd *board.Board, isBlack bool) []Continuation {
+
+	if bd.Size() > hash.MaxSize {
+		return nil
+	}
+
+	key, c, sym := position(bd, isBlack)
+	inverse := sym.Inverse()
+
+	var r []Continuation
+
+	for _, k := range b.positions[key] {
+
+		x, y := inverse.Apply(k.X, k.Y, c.Size())
+
+		r = append(r, Continuation{X: x, Y: y, Stats: k.Stats})
+	}
+
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].Games > r[j].Games
+	})
+
+	return r
+}
+
+// position returns the key of a position, its canonical pattern and the
+// symmetry mapping the board onto it.
+func position(bd *board.Board, isBlack bool) (key uint64, c hash.Pattern, sym hash.Symmetry) {
+
+	p := hash.NewPattern(bd.Size())
+
+	for y := 0; y < bd.Size(); y++ {
+		for x := 0; x < bd.Size(); x++ {
+
+			pt := bd.Point(x, y)
+
+			if bd.IsBlack(pt) {
+				p.SetBlack(x, y)
+			} else if bd.IsWhite(pt) {
+				p.SetWhite(x, y)
+			}
+		}
+	}
+
+	c, sym = p.CanonicalSymmetric()
+
+	key = c.GetSymmetricHash64()
+	if !isBlack {
+		key ^= board.WhiteToPlay
+	}
+
+	return key, c, sym
+}
+
+// canonicalMove maps a move to the canonical pattern, choosing the smallest
+// point among those equivalent under the symmetries of the pattern.
+func canonicalMove(c *hash.Pattern, sym hash.Symmetry, x, y int) (int, int) {
+
+	size := c.Size()
+
+	x, y = sym.Apply(x, y, size)
+
+	for s := hash.Symmetry(1); s < 8; s++ {
+
+		if c.Transform(s, false) != *c {
+			continue
+		}
+
+		tx, ty := s.Apply(x, y, size)
+
+		if ty*size+tx < y*size+x {
+			x, y = tx, ty
+		}
+	}
+
+	return x, y
+}
+
+// WriteTo writes the book in the binary file format.
+func (b *Book) WriteTo(w io.Writer) (int64, error) {
+
+	cw := countwriter.New(w)
+
+	keys := make([]uint64, 0, len(b.positions))
+	for k := range b.positions {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i] < keys[j]
+	})
+
+	cw.Put([]byte(magic))
+	cw.Put(binary.LittleEndian.AppendUint16(nil, version))
+	cw.Put(binary.LittleEndian.AppendUint32(nil, uint32(len(keys))))
+
+	var buf []byte
+
+	for _, k := range keys {
+
+		cs := append([]Continuation(nil), b.positions[k]...)
+
+		sort.Slice(cs, func(i, j int) bool {
+
+			if cs[i].Y != cs[j].Y {
+				return cs[i].Y < cs[j].Y
+			}
+
+			return cs[i].X < cs[j].X
+		})
+
+		buf = binary.LittleEndian.AppendUint64(buf[:0], k)
+		buf = binary.AppendUvarint(buf, uint64(len(cs)))
+
+		for _, c := range cs {
+			buf = append(buf, uint8(c.X), uint8(c.Y))
+			buf = binary.AppendUvarint(buf, c.Games)
+			buf = binary.AppendUvarint(buf, c.Wins)
+			buf = binary.AppendUvarint(buf, c.Losses)
+		}
+
+		cw.Put(buf)
+	}
+
+	return cw.Flush()
+}
+
+// Read reads a book written by WriteTo. MaxMoves is zero.
+func Read(r io.Reader) (*Book, error) {
+
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(magic)+2+4)
+
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("book: reading header: %v", err)
+	}
+
+	if string(header[:len(magic)]) != magic {
+		return nil, errors.New("book: not an opening book")
+	}
+
+	if v := binary.LittleEndian.Uint16(header[len(magic):]); v != version {
+		return nil, fmt.Errorf("book: unsupported version %d", v)
+	}
+
+	count := binary.LittleEndian.Uint32(header[len(magic)+2:])
+
+	b := New(0)
+
+	for i := uint32(0); i < count; i++ {
+
+		key, cs, err := readPosition(br)
+		if err != nil {
+			return nil, fmt.Errorf("book: reading position %d: %v", i, err)
+		}
+
+		b.positions[key] = cs
+	}
+
+	return b, nil
+}
+
+func readPosition(br *bufio.Reader) (uint64, []Continuation, error) {
+
+	var k [8]byte
+
+	if _, err := io.ReadFull(br, k[:]); err != nil {
+		return 0, nil, err
+	}
+
+	n, err := binary.ReadUvarint(br)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	var cs []Continuation
+
+	for j := uint64(0); j < n; j++ {
+
+		var xy [2]byte
+
+		if _, err := io.ReadFull(br, xy[:]); err != nil {
+			return 0, nil, err
+		}
+
+		c := Continuation{X: int(xy[0]), Y: int(xy[1])}
+
+		for _, v := range []*uint64{&c.Games, &c.Wins, &c.Losses} {
+			if *v, err = binary.ReadUvarint(br); err != nil {
+				return 0, nil, err
+			}
+		}
+
+		cs = append(cs, c)
+	}
+
+	return binary.LittleEndian.Uint64(k[:]), cs, nil
+}
@@ -0,0 +1,138 @@
+package book
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/sgf"
+)
+
+// Two games of the same opening in different orientations and move orders.
+const games = `
+(;SZ[19]RE[B+R];B[pd];W[dp];B[pp];W[dd])
+(;SZ[19]RE[W+3.5];B[dd];W[pp];B[dp];W[pd])
+(;SZ[19]RE[0];B[dp];W[pd];B[dd];W[pp])`
+
+func build(t *testing.T, maxMoves int) *Book {
+
+	roots, err := sgf.Parse(games)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	b := New(maxMoves)
+
+	for _, r := range roots {
+		if err := b.AddGame(r); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+
+	return b
+}
+
+func TestLookup(t *testing.T) {
+
+	b := build(t, 3)
+
+	cases := map[string]struct {
+		black, white [][2]int
+		isBlack      bool
+		expected     []Continuation
+	}{
+		"empty board": {
+			isBlack:  true,
+			expected: []Continuation{{3, 3, Stats{3, 1, 1}}},
+		},
+		"white to play on an empty board": {
+			isBlack: false,
+		},
+		"diagonal reply": {
+			black:    [][2]int{{3, 15}},
+			expected: []Continuation{{15, 3, Stats{3, 1, 1}}},
+		},
+		// The 3-3 and 15-15 points are the same move in this position.
+		"symmetric position": {
+			black:    [][2]int{{3, 15}},
+			white:    [][2]int{{15, 3}},
+			isBlack:  true,
+			expected: []Continuation{{15, 15, Stats{3, 1, 1}}},
+		},
+		"past the last book move": {
+			black: [][2]int{{3, 3}, {3, 15}},
+			white: [][2]int{{15, 15}},
+		},
+	}
+
+	for k, tc := range cases {
+
+		bd := board.NewBoard(19)
+
+		for _, p := range tc.black {
+			bd.DoBlack(bd.Point(p[0], p[1]))
+		}
+
+		for _, p := range tc.white {
+			bd.DoWhite(bd.Point(p[0], p[1]))
+		}
+
+		if r := b.Lookup(&bd, tc.isBlack); !reflect.DeepEqual(r, tc.expected) {
+			t.Errorf("%s: %v, expected %v", k, r, tc.expected)
+		}
+	}
+
+	if s := (Stats{4, 1, 1}); s.WinRate() != 0.5 {
+		t.Errorf("win rate %v, expected 0.5", s.WinRate())
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+
+	b := build(t, 10)
+
+	var buf bytes.Buffer
+
+	n, err := b.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if n != int64(buf.Len()) {
+		t.Errorf("wrote %d bytes, reported %d", buf.Len(), n)
+	}
+
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if read.Len() != b.Len() {
+		t.Fatalf("read %d positions, expected %d", read.Len(), b.Len())
+	}
+
+	for k, cs := range b.positions {
+		for _, c := range cs {
+			if s := read.find(k, c.X, c.Y); *s != c.Stats {
+				t.Errorf("%#x (%d, %d): %+v, expected %+v", k, c.X, c.Y, *s, c.Stats)
+			}
+		}
+	}
+}
+
+func TestReadError(t *testing.T) {
+
+	cases := map[string][]byte{
+		"empty":     {},
+		"magic":     []byte("ABCD\x01\x00\x00\x00\x00\x00"),
+		"version":   []byte("GXOB\x09\x00\x00\x00\x00\x00"),
+		"truncated": []byte("GXOB\x01\x00\x01\x00\x00\x00\x03"),
+	}
+
+	for k, tc := range cases {
+		if _, err := Read(bytes.NewReader(tc)); err == nil {
+			t.Errorf("%s: read without error", k)
+		}
+	}
+}
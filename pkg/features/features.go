@@ -0,0 +1,220 @@
+/*
+Package features encodes a Go position as input planes for a neural network.
+
+The planes follow AlphaGo and Leela Zero. Colors are relative to the player
+to move, so "own" is black when black is to play.
+
+	Plane   Count   Feature
+	0       1       own stones
+	1       1       opponent stones
+	2       1       empty points
+	3       8       liberties of the chain on a stone: 1, 2, ..., 7, 8+
+	11      8       turns since a stone was played: 1, 2, ..., 7, 8+
+	19      8       stones captured by playing a point: 0, 1, ..., 6, 7+
+	27      8       own stones in atari after playing a point: 1, 2, ..., 7, 8+
+	35      1       playing a point captures an opponent chain in a ladder
+	36      1       playing a point escapes an own chain from a ladder
+	37      1       playing a point is legal
+	38      1       ko point
+	39      1       constant one
+
+The tensor is flat with index plane*size*size + y*size + x.
+*/
+package features
+
+import (
+	"github.com/gosharplite/goxit/pkg/board"
+)
+
+// Offsets of each feature in the tensor.
+const (
+	Own        = 0
+	Opponent   = 1
+	Empty      = 2
+	Liberties  = 3
+	TurnsSince = 11
+	Capture    = 19
+	SelfAtari  = 27
+	LadderCap  = 35
+	LadderEsc  = 36
+	Legal      = 37
+	Ko         = 38
+	Ones       = 39
+
+	// NumPlanes is the number of planes in the tensor.
+	NumPlanes = 40
+)
+
+// Encode returns the planes of a position as a flat tensor of zeros and ones.
+// The board is used for reading ahead and is restored before returning.
+func Encode(bd *board.Board) []uint8 {
+
+	e := newEncoder(bd)
+	e.encode()
+
+	return e.planes
+}
+
+// EncodeFloat32 is Encode with float32 values.
+func EncodeFloat32(bd *board.Board) []float32 {
+
+	return toFloat32(Encode(bd))
+}
+
+type encoder struct {
+	bd *board.Board
+
+	size int
+
+	// Color of the player to move.
+	blackToPlay bool
+
+	planes []uint8
+}
+
+func newEncoder(bd *board.Board) encoder {
+
+	e := encoder{}
+
+	e.bd = bd
+	e.size = bd.Size()
+	e.blackToPlay = bd.BlackToPlay()
+	e.planes = make([]uint8, NumPlanes*e.size*e.size)
+
+	return e
+}
+
+func (e *encoder) set(plane, x, y int) {
+
+	e.planes[plane*e.size*e.size+y*e.size+x] = 1
+}
+
+func (e *encoder) isOwn(pt int) bool {
+
+	if e.blackToPlay {
+		return e.bd.IsBlack(pt)
+	}
+
+	return e.bd.IsWhite(pt)
+}
+
+func (e *encoder) encode() {
+
+	turns := e.turnsSince()
+
+	for y := 0; y < e.size; y++ {
+		for x := 0; x < e.size; x++ {
+
+			pt := e.bd.Point(x, y)
+
+			e.set(Ones, x, y)
+
+			if e.bd.IsEmpty(pt) {
+
+				e.set(Empty, x, y)
+
+				e.encodeMove(pt, x, y)
+
+				continue
+			}
+
+			if e.isOwn(pt) {
+				e.set(Own, x, y)
+			} else {
+				e.set(Opponent, x, y)
+			}
+
+			e.set(Liberties+bucket(e.bd.NumLiberties(pt)-1), x, y)
+
+			if t, ok := turns[pt]; ok {
+				e.set(TurnsSince+bucket(t-1), x, y)
+			}
+		}
+	}
+
+	if ko := e.bd.KoPoint(); ko != 0 {
+		x, y := e.bd.XY(ko)
+		e.set(Ko, x, y)
+	}
+}
+
+// turnsSince maps each stone on the board to the number of turns since it was played.
+func (e *encoder) turnsSince() map[int]int {
+
+	r := map[int]int{}
+
+	depth := e.bd.Depth()
+
+	for n := depth; n >= 1; n-- {
+
+		pt, _ := e.bd.Move(n)
+
+		// Passes and setup stones are not played stones.
+		if pt == 0 || e.bd.IsSetup(n) {
+			continue
+		}
+
+		if _, ok := r[pt]; ok || e.bd.IsEmpty(pt) {
+			continue
+		}
+
+		r[pt] = depth - n + 1
+	}
+
+	return r
+}
+
+// encodeMove sets the planes which require playing on an empty point.
+func (e *encoder) encodeMove(pt, x, y int) {
+
+	bb, wb := e.bd.Prisoners()
+
+	if e.bd.Do(pt, e.blackToPlay) != nil {
+		return
+	}
+
+	e.set(Legal, x, y)
+
+	ba, wa := e.bd.Prisoners()
+
+	e.set(Capture+bucket(ba-bb+wa-wb), x, y)
+
+	if e.bd.NumLiberties(pt) == 1 {
+		e.set(SelfAtari+bucket(len(e.bd.Chain(pt))-1), x, y)
+	}
+
+	e.bd.Undo()
+
+	if isLadderCapture(e.bd, pt, e.blackToPlay) {
+		e.set(LadderCap, x, y)
+	}
+
+	if isLadderEscape(e.bd, pt, e.blackToPlay) {
+		e.set(LadderEsc, x, y)
+	}
+}
+
+// bucket clamps a value into the range of an eight plane feature.
+func bucket(v int) int {
+
+	if v < 0 {
+		return 0
+	}
+
+	if v > 7 {
+		return 7
+	}
+
+	return v
+}
+
+func toFloat32(u []uint8) []float32 {
+
+	r := make([]float32, len(u))
+
+	for i, v := range u {
+		r[i] = float32(v)
+	}
+
+	return r
+}
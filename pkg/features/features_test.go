@@ -0,0 +1,165 @@
+package features
+
+import (
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/board"
+)
+
+func get(planes []uint8, size, plane, x, y int) uint8 {
+
+	return planes[plane*size*size+y*size+x]
+}
+
+func TestEncode(t *testing.T) {
+
+	bh := board.NewBoard(5)
+
+	bh.DoBlack(bh.Point(1, 1))
+	bh.DoWhite(bh.Point(2, 1))
+	bh.DoBlack(bh.Point(3, 3))
+
+	p := Encode(&bh)
+
+	if len(p) != NumPlanes*25 {
+		t.Fatalf("length %d, expected %d", len(p), NumPlanes*25)
+	}
+
+	// White to play.
+	cases := map[string]struct {
+		plane, x, y int
+		expected    uint8
+	}{
+		"own":        {Own, 2, 1, 1},
+		"opponent":   {Opponent, 1, 1, 1},
+		"empty":      {Empty, 0, 0, 1},
+		"4 liberty":  {Liberties + 3, 3, 3, 1},
+		"3 liberty":  {Liberties + 2, 1, 1, 1},
+		"last move":  {TurnsSince, 3, 3, 1},
+		"first move": {TurnsSince + 2, 1, 1, 1},
+		"legal":      {Legal, 0, 0, 1},
+		"occupied":   {Legal, 1, 1, 0},
+		"capture 0":  {Capture, 0, 0, 1},
+		"ones":       {Ones, 4, 4, 1},
+	}
+
+	for k, tc := range cases {
+		actual := get(p, 5, tc.plane, tc.x, tc.y)
+		if actual != tc.expected {
+			t.Errorf("%s: plane %d (%d, %d) = %d, expected %d", k, tc.plane, tc.x, tc.y, actual, tc.expected)
+		}
+	}
+}
+
+func TestSelfAtariAndCapture(t *testing.T) {
+
+	bh := board.NewBoard(5)
+
+	bh.DoBlack(bh.Point(2, 0))
+	bh.DoWhite(bh.Point(1, 4))
+	bh.DoBlack(bh.Point(1, 1))
+	bh.DoWhite(bh.Point(4, 4))
+	bh.DoBlack(bh.Point(0, 4))
+
+	// White to play.
+	p := Encode(&bh)
+
+	cases := map[string]struct {
+		plane, x, y int
+		expected    uint8
+	}{
+		"self-atari":     {SelfAtari, 1, 0, 1},
+		"not self-atari": {SelfAtari, 3, 3, 0},
+		"capture 1":      {Capture + 1, 0, 3, 1},
+		"capture 0":      {Capture, 0, 3, 0},
+		"occupied":       {Legal, 0, 4, 0},
+	}
+
+	for k, tc := range cases {
+		actual := get(p, 5, tc.plane, tc.x, tc.y)
+		if actual != tc.expected {
+			t.Errorf("%s: plane %d (%d, %d) = %d, expected %d", k, tc.plane, tc.x, tc.y, actual, tc.expected)
+		}
+	}
+}
+
+func TestLadder(t *testing.T) {
+
+	bh := board.NewBoard(9)
+
+	// White stone with black on its north and west sides.
+	bh.DoBlack(bh.Point(4, 3))
+	bh.DoWhite(bh.Point(4, 4))
+	bh.DoBlack(bh.Point(3, 4))
+	bh.DoWhite(bh.Point(0, 8))
+	bh.DoBlack(bh.Point(5, 5))
+	bh.DoWhite(bh.Point(8, 8))
+
+	// Black to play. Pushing from below ladders white towards the top right.
+	if !isLadderCapture(&bh, bh.Point(4, 5), true) {
+		t.Error("(4, 5) is a ladder capture")
+	}
+
+	// A white ladder breaker.
+	bh.DoBlack(bh.Point(0, 0))
+	bh.DoWhite(bh.Point(7, 1))
+	bh.DoBlack(bh.Point(1, 0))
+
+	if isLadderCapture(&bh, bh.Point(4, 5), true) {
+		t.Error("(4, 5) is broken by a ladder breaker")
+	}
+
+	bh.DoWhite(bh.Point(8, 0))
+
+	// Black to play; white at (4, 4) escaping from (5, 4) is now possible.
+	bh.DoBlack(bh.Point(4, 5))
+
+	p := Encode(&bh)
+
+	if get(p, 9, LadderEsc, 5, 4) != 1 {
+		t.Error("(5, 4) is a ladder escape")
+	}
+}
+
+func TestTransform(t *testing.T) {
+
+	for s := 0; s < NumSymmetries; s++ {
+
+		x, y := TransformXY(1, 2, 7, s)
+		ix, iy := InverseXY(x, y, 7, s)
+
+		if ix != 1 || iy != 2 {
+			t.Errorf("symmetry %d: (1, 2) -> (%d, %d) -> (%d, %d)", s, x, y, ix, iy)
+		}
+	}
+
+	moves := [][2]int{{1, 0}, {2, 3}, {0, 4}, {4, 1}}
+
+	bh := board.NewBoard(5)
+
+	for i, m := range moves {
+		bh.Do(bh.Point(m[0], m[1]), i%2 == 0)
+	}
+
+	p := Encode(&bh)
+
+	for s := 0; s < NumSymmetries; s++ {
+
+		tb := board.NewBoard(5)
+
+		for i, m := range moves {
+			x, y := TransformXY(m[0], m[1], 5, s)
+			tb.Do(tb.Point(x, y), i%2 == 0)
+		}
+
+		expected := Encode(&tb)
+		actual := Transform(p, 5, s)
+
+		for i := range expected {
+			if actual[i] != expected[i] {
+				t.Errorf("symmetry %d: index %d = %d, expected %d", s, i, actual[i], expected[i])
+				break
+			}
+		}
+	}
+}
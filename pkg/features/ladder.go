@@ -0,0 +1,137 @@
+package features
+
+import (
+	"github.com/gosharplite/goxit/pkg/board"
+)
+
+// maxLadderDepth bounds the number of moves read in a ladder.
+const maxLadderDepth = 80
+
+// isLadderCapture reports whether playing pt puts an adjacent opponent chain
+// in atari which then cannot escape the ladder.
+func isLadderCapture(bd *board.Board, pt int, isBlack bool) bool {
+
+	if bd.Do(pt, isBlack) != nil {
+		return false
+	}
+	defer bd.Undo()
+
+	for _, n := range bd.Neighbors(pt) {
+
+		if !isColor(bd, n, !isBlack) || bd.NumLiberties(n) != 1 {
+			continue
+		}
+
+		if ladderCaptured(bd, n, !isBlack, 0) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isLadderEscape reports whether playing pt rescues an adjacent own chain
+// in atari which would otherwise be captured in a ladder.
+func isLadderEscape(bd *board.Board, pt int, isBlack bool) bool {
+
+	for _, n := range bd.Neighbors(pt) {
+
+		if !isColor(bd, n, isBlack) || bd.NumLiberties(n) != 1 {
+			continue
+		}
+
+		if bd.Do(pt, isBlack) != nil {
+			return false
+		}
+
+		escaped := bd.NumLiberties(n) >= 3 || bd.NumLiberties(n) == 2 && !ladderAttack(bd, n, !isBlack, 0)
+
+		bd.Undo()
+
+		if escaped {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ladderCaptured reports whether the chain on pt, which is in atari with its
+// owner to move, is captured in a ladder.
+func ladderCaptured(bd *board.Board, pt int, isBlack bool, depth int) bool {
+
+	if depth > maxLadderDepth {
+		return false
+	}
+
+	for _, m := range escapes(bd, pt, isBlack) {
+
+		if bd.Do(m, isBlack) != nil {
+			continue
+		}
+
+		libs := bd.NumLiberties(pt)
+
+		captured := libs < 2 || libs == 2 && ladderAttack(bd, pt, !isBlack, depth+1)
+
+		bd.Undo()
+
+		if !captured {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ladderAttack reports whether the attacker can capture the chain on pt,
+// which has two liberties, by continuing the ladder.
+func ladderAttack(bd *board.Board, pt int, isBlack bool, depth int) bool {
+
+	for _, m := range bd.Liberties(pt) {
+
+		if bd.Do(m, isBlack) != nil {
+			continue
+		}
+
+		// An attacking stone which can be captured breaks the ladder.
+		captured := bd.NumLiberties(m) > 1 && ladderCaptured(bd, pt, !isBlack, depth+1)
+
+		bd.Undo()
+
+		if captured {
+			return true
+		}
+	}
+
+	return false
+}
+
+// escapes returns the moves which may save the chain on pt: extending on its
+// liberty, or capturing an adjacent attacking chain in atari.
+func escapes(bd *board.Board, pt int, isBlack bool) []int {
+
+	r := bd.Liberties(pt)
+
+	for _, p := range bd.Chain(pt) {
+		for _, n := range bd.Neighbors(p) {
+
+			if !isColor(bd, n, !isBlack) || bd.NumLiberties(n) != 1 {
+				continue
+			}
+
+			r = append(r, bd.Liberties(n)[0])
+		}
+	}
+
+	return r
+}
+
+func isColor(bd *board.Board, pt int, isBlack bool) bool {
+
+	if isBlack {
+		return bd.IsBlack(pt)
+	}
+
+	return bd.IsWhite(pt)
+}
@@ -0,0 +1,90 @@
+package features
+
+// NumSymmetries is the number of symmetries of a square board.
+const NumSymmetries = 8
+
+// TransformXY maps a coordinate by one of the 8 symmetries. Symmetries 0 to 3
+// rotate by 0, 90, 180 and 270 degrees, symmetries 4 to 7 mirror x first.
+func TransformXY(x, y, size, sym int) (int, int) {
+
+	if sym >= 4 {
+		x = size - 1 - x
+	}
+
+	for i := 0; i < sym%4; i++ {
+		x, y = size-1-y, x
+	}
+
+	return x, y
+}
+
+// InverseXY undoes TransformXY.
+func InverseXY(x, y, size, sym int) (int, int) {
+
+	for i := 0; i < sym%4; i++ {
+		x, y = y, size-1-x
+	}
+
+	if sym >= 4 {
+		x = size - 1 - x
+	}
+
+	return x, y
+}
+
+// Transform returns a copy of a tensor with every plane mapped by a symmetry.
+func Transform(planes []uint8, size, sym int) []uint8 {
+
+	r := make([]uint8, len(planes))
+
+	for i, j := range permutation(len(planes), size, sym) {
+		r[j] = planes[i]
+	}
+
+	return r
+}
+
+// TransformFloat32 is Transform for float32 tensors.
+func TransformFloat32(planes []float32, size, sym int) []float32 {
+
+	r := make([]float32, len(planes))
+
+	for i, j := range permutation(len(planes), size, sym) {
+		r[j] = planes[i]
+	}
+
+	return r
+}
+
+// Augment returns the tensor under all 8 symmetries, indexed by symmetry.
+func Augment(planes []uint8, size int) [][]uint8 {
+
+	r := make([][]uint8, NumSymmetries)
+
+	for s := 0; s < NumSymmetries; s++ {
+		r[s] = Transform(planes, size, s)
+	}
+
+	return r
+}
+
+// permutation maps each tensor index to its index under a symmetry.
+func permutation(length, size, sym int) []int {
+
+	area := size * size
+
+	r := make([]int, length)
+
+	for i := range r {
+
+		plane := i / area
+		x := i % size
+		y := i % area / size
+
+		tx, ty := TransformXY(x, y, size, sym)
+
+		r[i] = plane*area + ty*size + tx
+	}
+
+	return r
+}
@@ -0,0 +1,128 @@
+package hash
+
+import (
+	"math/bits"
+)
+
+// MaxSize is the largest pattern size.
+const MaxSize = 25
+
+// maxWords is the number of 64 bit words of a MaxSize pattern.
+const maxWords = (MaxSize*MaxSize + 63) / 64
+
+// A bitBoard is a set of cells of a square pattern, one bit per cell with
+// cell (x, y) at bit y*size + x. Its capacity is fixed so patterns are
+// values and never allocate.
+type bitBoard [maxWords]uint64
+
+// numWords returns the number of words used by a size by size pattern.
+func numWords(size int) int {
+
+	return (size*size + 63) / 64
+}
+
+func (b *bitBoard) set(i int) {
+
+	b[i/64] |= 1 << uint(i%64)
+}
+
+func (b *bitBoard) clear(i int) {
+
+	b[i/64] &^= 1 << uint(i%64)
+}
+
+func (b *bitBoard) test(i int) bool {
+
+	return b[i/64]&(1<<uint(i%64)) != 0
+}
+
+// row returns the size bits of row y.
+func (b *bitBoard) row(y, size int) uint32 {
+
+	i := y * size
+	w := i / 64
+	s := uint(i % 64)
+
+	r := b[w] >> s
+
+	// The row continues in the next word.
+	if s+uint(size) > 64 {
+		r |= b[w+1] << (64 - s)
+	}
+
+	return uint32(r) & (1<<uint(size) - 1)
+}
+
+// orRow sets the bits of row y which are set in r.
+func (b *bitBoard) orRow(y, size int, r uint32) {
+
+	i := y * size
+	w := i / 64
+	s := uint(i % 64)
+
+	b[w] |= uint64(r) << s
+
+	if s+uint(size) > 64 {
+		b[w+1] |= uint64(r) >> (64 - s)
+	}
+}
+
+// symmetryFlips decomposes each Symmetry into a transpose followed by a
+// horizontal and a vertical flip.
+var symmetryFlips = [8][3]bool{
+	{false, false, false},
+	{true, false, true},
+	{false, true, true},
+	{true, true, false},
+	{false, true, false},
+	{true, true, true},
+	{false, false, true},
+	{true, false, false},
+}
+
+// transform sets b to src mapped by a symmetry. Rows are moved and mirrored
+// with shifts; only a transpose visits single cells.
+func (b *bitBoard) transform(src *bitBoard, s Symmetry, size int) {
+
+	var rows [MaxSize]uint32
+
+	f := symmetryFlips[s]
+
+	if f[0] {
+
+		for w, v := range src[:numWords(size)] {
+			for v != 0 {
+
+				i := w*64 + bits.TrailingZeros64(v)
+				v &= v - 1
+
+				// Cell (x, y) moves to (y, x).
+				rows[i%size] |= 1 << uint(i/size)
+			}
+		}
+
+	} else {
+
+		for y := 0; y < size; y++ {
+			rows[y] = src.row(y, size)
+		}
+	}
+
+	*b = bitBoard{}
+
+	for y := 0; y < size; y++ {
+
+		r := rows[y]
+
+		if f[1] {
+			r = bits.Reverse32(r) >> uint(32-size)
+		}
+
+		ty := y
+		if f[2] {
+			ty = size - 1 - y
+		}
+
+		b.orRow(ty, size, r)
+	}
+}
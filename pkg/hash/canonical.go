@@ -0,0 +1,165 @@
+package hash
+
+import (
+	"math/bits"
+)
+
+// A transformer finds the canonical transform of a pattern. Transforms are
+// computed one at a time into reused bit boards.
+type transformer struct {
+	size int
+	l    int
+	n    int
+
+	black bitBoard
+	white bitBoard
+
+	// Current transform.
+	black1 bitBoard
+	white1 bitBoard
+
+	// Best transform so far.
+	bestBlack bitBoard
+	bestWhite bitBoard
+}
+
+func (p *Pattern) newTransformer() transformer {
+
+	return transformer{
+		size:      p.size,
+		l:         p.size * p.size,
+		n:         numWords(p.size),
+		black:     p.black,
+		white:     p.white,
+		bestBlack: p.black,
+		bestWhite: p.white,
+	}
+}
+
+// reverse swaps the colors of the pattern before any transform is computed.
+func (t *transformer) reverse() {
+
+	t.black, t.white = t.white, t.black
+	t.bestBlack, t.bestWhite = t.bestWhite, t.bestBlack
+}
+
+// transform computes transform j into black1 and white1. Transforms 0 to 7
+// are the symmetries, 8 to 15 the same with colors reversed.
+func (t *transformer) transform(j int) {
+
+	src0, src1 := &t.black, &t.white
+	if j >= 8 {
+		src0, src1 = src1, src0
+	}
+
+	t.black1.transform(src0, Symmetry(j%8), t.size)
+	t.white1.transform(src1, Symmetry(j%8), t.size)
+}
+
+// keep copies the current transform to the best transform.
+func (t *transformer) keep() {
+
+	t.bestBlack = t.black1
+	t.bestWhite = t.white1
+}
+
+// legacy selects the canonical transform of GetHash and returns its index.
+// It compares white stones only and ignores the first cell, which must not
+// change as stored hash values depend on it.
+func (t *transformer) legacy() int {
+
+	index := 0
+
+	for j := 1; j < 16; j++ {
+
+		t.transform(j)
+
+		n := firstDiff(&t.bestWhite, &t.white1, t.l)
+
+		if n > 0 && !t.bestWhite.test(n) {
+
+			t.keep()
+
+			index = j
+		}
+	}
+
+	return index
+}
+
+// strict selects the smallest of the first n transforms comparing every
+// cell, so equal patterns always share a canonical form, and returns its index.
+func (t *transformer) strict(n int) int {
+
+	index := 0
+
+	for j := 1; j < n; j++ {
+
+		t.transform(j)
+
+		if t.less() {
+
+			t.keep()
+
+			index = j
+		}
+	}
+
+	return index
+}
+
+// less reports whether the current transform is smaller than the best one,
+// comparing cells as empty 0, black 1, white 2 and edge 3.
+func (t *transformer) less() bool {
+
+	for w := 0; w < t.n; w++ {
+
+		d := (t.black1[w] ^ t.bestBlack[w]) | (t.white1[w] ^ t.bestWhite[w])
+		if d == 0 {
+			continue
+		}
+
+		i := w*64 + bits.TrailingZeros64(d)
+
+		return cell(&t.black1, &t.white1, i) < cell(&t.bestBlack, &t.bestWhite, i)
+	}
+
+	return false
+}
+
+// firstDiff returns the first of the first l bits where a and b differ, -1 if none.
+func firstDiff(a, b *bitBoard, l int) int {
+
+	for w := range a {
+
+		d := a[w] ^ b[w]
+		if d == 0 {
+			continue
+		}
+
+		i := w*64 + bits.TrailingZeros64(d)
+		if i >= l {
+			return -1
+		}
+
+		return i
+	}
+
+	return -1
+}
+
+// cell encodes cell i as empty 0, black 1, white 2 and edge 3.
+func cell(b, w *bitBoard, i int) int {
+
+	r := 0
+
+	if b.test(i) {
+		r |= 1
+	}
+
+	if w.test(i) {
+		r |= 2
+	}
+
+	return r
+}
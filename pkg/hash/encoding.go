@@ -0,0 +1,88 @@
+package hash
+
+import (
+	"errors"
+	"fmt"
+)
+
+// MarshalBinary encodes the pattern as its size in one byte followed by the
+// cells in row order, 2 bits each as empty 0, black 1, white 2 and edge 3,
+// four cells per byte starting from the low bits.
+func (p *Pattern) MarshalBinary() ([]byte, error) {
+
+	l := p.size * p.size
+
+	r := make([]byte, 1+(l+3)/4)
+	r[0] = byte(p.size)
+
+	for i := 0; i < l; i++ {
+		r[1+i/4] |= byte(cell(&p.black, &p.white, i)) << uint(2*(i%4))
+	}
+
+	return r, nil
+}
+
+// UnmarshalBinary decodes a pattern encoded by MarshalBinary.
+func (p *Pattern) UnmarshalBinary(data []byte) error {
+
+	if len(data) == 0 {
+		return errors.New("hash: empty pattern encoding")
+	}
+
+	size := int(data[0])
+
+	if size > MaxSize {
+		return fmt.Errorf("hash: pattern size %d is larger than %d", size, MaxSize)
+	}
+
+	l := size * size
+
+	if len(data) != 1+(l+3)/4 {
+		return fmt.Errorf("hash: pattern of size %d encoded in %d bytes, expected %d", size, len(data), 1+(l+3)/4)
+	}
+
+	q := NewPattern(size)
+
+	for i := 0; i < l; i++ {
+
+		c := data[1+i/4] >> uint(2*(i%4)) & 3
+
+		if c&1 != 0 {
+			q.black.set(i)
+		}
+
+		if c&2 != 0 {
+			q.white.set(i)
+		}
+	}
+
+	// Bits after the last cell must be zero so each pattern has one encoding.
+	if l%4 != 0 && data[len(data)-1]>>uint(2*(l%4)) != 0 {
+		return errors.New("hash: pattern encoding has trailing bits set")
+	}
+
+	*p = q
+
+	return nil
+}
+
+// MarshalText encodes the pattern as the diagram of String.
+func (p *Pattern) MarshalText() ([]byte, error) {
+
+	return []byte(p.String()), nil
+}
+
+// UnmarshalText decodes a diagram in the notation of ParsePattern. Unlike
+// ParsePattern it also accepts even sizes, and an empty diagram is a pattern
+// of size 0, so every pattern survives MarshalText.
+func (p *Pattern) UnmarshalText(text []byte) error {
+
+	q, err := parseRows(diagramLines(string(text)))
+	if err != nil {
+		return err
+	}
+
+	*p = q
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package hash
+
+// An Enumerator yields one pattern of each equivalence class of patterns
+// with a given size and edges, classes being those of GetHash64: mirrored,
+// rotated and color reversed patterns are equal. Patterns are generated one
+// at a time, so memory does not grow with the number of classes.
+type Enumerator struct {
+	size int
+
+	// Cells which are not edges, each empty, black or white.
+	free   []int
+	digits []uint8
+
+	// Transforms mapping the edges onto themselves, except the identity.
+	group []int
+
+	p    Pattern
+	done bool
+}
+
+// NewEnumerator create an Enumerator object for size by size patterns with
+// left, top, right and bottom columns and rows of edge cells, the way
+// Board.Pattern sees a point near the edge of the board.
+func NewEnumerator(size, left, top, right, bottom int) *Enumerator {
+
+	e := &Enumerator{
+		size: size,
+		p:    NewPattern(size),
+	}
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+
+			if x < left || y < top || x >= size-right || y >= size-bottom {
+				e.p.SetBlack(x, y)
+				e.p.SetWhite(x, y)
+				continue
+			}
+
+			e.free = append(e.free, y*size+x)
+		}
+	}
+
+	e.digits = make([]uint8, len(e.free))
+
+	for j := 1; j < 16; j++ {
+
+		var b bitBoard
+		b.transform(&e.p.black, Symmetry(j%8), size)
+
+		if b == e.p.black {
+			e.group = append(e.group, j)
+		}
+	}
+
+	return e
+}
+
+// Next returns the next pattern, false when all classes have been yielded.
+// Each pattern is the smallest of its class with the same edges, in the
+// cell order GetHash64 compares.
+func (e *Enumerator) Next() (Pattern, bool) {
+
+	for !e.done {
+
+		p := e.p
+		ok := e.isSmallest()
+
+		e.advance()
+
+		if ok {
+			return p, true
+		}
+	}
+
+	return Pattern{}, false
+}
+
+// isSmallest reports whether no transform of the current pattern is smaller.
+func (e *Enumerator) isSmallest() bool {
+
+	t := e.p.newTransformer()
+
+	for _, j := range e.group {
+
+		t.transform(j)
+
+		if t.less() {
+			return false
+		}
+	}
+
+	return true
+}
+
+// advance counts the free cells up in base 3 as empty, black and white.
+func (e *Enumerator) advance() {
+
+	for i, c := range e.free {
+
+		e.digits[i] = (e.digits[i] + 1) % 3
+
+		e.p.black.clear(c)
+		e.p.white.clear(c)
+
+		switch e.digits[i] {
+		case 1:
+			e.p.black.set(c)
+		case 2:
+			e.p.white.set(c)
+		}
+
+		if e.digits[i] != 0 {
+			return
+		}
+	}
+
+	e.done = true
+}
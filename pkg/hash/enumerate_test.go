@@ -0,0 +1,108 @@
+package hash
+
+import (
+	"testing"
+)
+
+// burnside counts the classes of patterns with edges by Burnside's lemma: the
+// average over the transforms keeping the edges of the number of colorings
+// of the free cells each one fixes.
+func burnside(size, left, top, right, bottom int) int {
+
+	e := NewEnumerator(size, left, top, right, bottom)
+
+	total := 0
+
+	for _, j := range append([]int{0}, e.group...) {
+
+		fixed := 1
+		seen := map[int]bool{}
+
+		for _, c := range e.free {
+
+			if seen[c] {
+				continue
+			}
+
+			// Walk the cycle of c.
+			n := 0
+			for i := c; !seen[i]; n++ {
+
+				seen[i] = true
+
+				x, y := Symmetry(j%8).Apply(i%size, i/size, size)
+				i = y*size + x
+			}
+
+			switch {
+			case j < 8:
+				fixed *= 3
+			case n%2 == 1:
+				// Reversing colors along an odd cycle leaves only empty cells.
+				fixed *= 1
+			default:
+				fixed *= 3
+			}
+		}
+
+		total += fixed
+	}
+
+	return total / (len(e.group) + 1)
+}
+
+func TestEnumerator(t *testing.T) {
+
+	cases := map[string]struct {
+		size                     int
+		left, top, right, bottom int
+		expected                 int
+	}{
+		"3x3":         {3, 0, 0, 0, 0, 1444},
+		"3x3 side":    {3, 0, 1, 0, 0, 205},
+		"3x3 corner":  {3, 1, 1, 0, 0, 28},
+		"4x4 corner":  {4, 2, 2, 0, 0, 28},
+		"5x5 corner":  {5, 2, 2, 0, 0, 5110},
+		"5x5 side":    {5, 0, 3, 0, 0, 14965},
+		"all edges":   {3, 3, 0, 0, 0, 1},
+		"single cell": {1, 0, 0, 0, 0, 2},
+	}
+
+	for k, tc := range cases {
+
+		expected := burnside(tc.size, tc.left, tc.top, tc.right, tc.bottom)
+
+		if expected != tc.expected {
+			t.Errorf("%s: burnside count %d, expected %d", k, expected, tc.expected)
+		}
+
+		// Every pattern with the edges must hash like one of the classes.
+		classes := map[uint64]bool{}
+
+		e := NewEnumerator(tc.size, tc.left, tc.top, tc.right, tc.bottom)
+
+		for p, ok := e.Next(); ok; p, ok = e.Next() {
+
+			h := p.GetHash64()
+
+			if classes[h] {
+				t.Fatalf("%s: class yielded twice\n%s", k, p.String())
+			}
+
+			classes[h] = true
+		}
+
+		if len(classes) != expected {
+			t.Errorf("%s: %d classes, burnside count %d", k, len(classes), expected)
+		}
+
+		all := NewEnumerator(tc.size, tc.left, tc.top, tc.right, tc.bottom)
+		all.group = nil
+
+		for p, ok := all.Next(); ok; p, ok = all.Next() {
+			if !classes[p.GetHash64()] {
+				t.Fatalf("%s: class of\n%s\nnot yielded", k, p.String())
+			}
+		}
+	}
+}
@@ -20,15 +20,12 @@ The edge of the board can be modeled by setting both black and white at the same
 package hash
 
 import (
-	"github.com/willf/bitset"
+	"errors"
+	"fmt"
+	"strings"
 )
 
 var (
-	mirror = []int{1, 0, 0, -1}
-	rot90  = []int{0, -1, 1, 0}
-	rot180 = []int{-1, 0, 0, -1}
-	rot270 = []int{0, 1, -1, 0}
-
 	maxUint64 uint64 = 18446744073709551615
 	prime     uint64 = 13
 	wordLimit uint64 = maxUint64 / 2
@@ -39,10 +36,11 @@ var (
 type Pattern struct {
 	size int
 
-	black *bitset.BitSet
-	white *bitset.BitSet
+	black bitBoard
+	white bitBoard
 }
 
+// NewPattern create an empty Pattern object. Size is at most MaxSize.
 func NewPattern(size int) Pattern {
 
 	p := Pattern{}
@@ -53,211 +51,196 @@ func NewPattern(size int) Pattern {
 
 func (p *Pattern) init(size int) {
 
+	if size < 0 || size > MaxSize {
+		panic(fmt.Sprintf("hash: pattern size %d is not between 0 and %d", size, MaxSize))
+	}
+
 	p.size = size
 
-	p.black = bitset.New(uint(size * size))
-	p.white = bitset.New(uint(size * size))
+	p.black = bitBoard{}
+	p.white = bitBoard{}
 }
 
-func (p *Pattern) SetBlack(x, y int) {
-
-	q := uint(y*p.size + x)
+// ParsePattern creates a Pattern from a text diagram with one row per line,
+// using X for black, O for white, . for empty and # for edge. Blank lines and
+// surrounding spaces are ignored. The diagram must be square with an odd size.
+//
+//	.#..O
+//	.#.X.
+//	.#.X.
+//	.####
+//	.....
+func ParsePattern(s string) (Pattern, error) {
+
+	rows, err := diagramRows(s)
+	if err != nil {
+		return Pattern{}, err
+	}
 
-	p.black.Set(q)
+	return parseRows(rows)
 }
 
-func (p *Pattern) SetWhite(x, y int) {
-
-	q := uint(y*p.size + x)
-
-	p.white.Set(q)
-}
+// parseRows creates a Pattern from the rows of a diagram.
+func parseRows(rows []string) (Pattern, error) {
 
-func (p *Pattern) canonical() (black *bitset.BitSet, white *bitset.BitSet) {
+	size := len(rows)
 
-	l := uint(p.size * p.size)
+	if size > MaxSize {
+		return Pattern{}, fmt.Errorf("hash: pattern size %d is larger than %d", size, MaxSize)
+	}
 
-	blacks := p.initBitSet(p.black, l)
-	whites := p.initBitSet(p.white, l)
+	p := NewPattern(size)
 
-	for i := uint(0); i < l; i++ {
+	for y, row := range rows {
 
-		if p.black.Test(i) {
-			p.translate(i, blacks)
+		if len(row) != size {
+			return Pattern{}, fmt.Errorf("hash: row %d has %d cells, expected %d", y+1, len(row), size)
 		}
 
-		if p.white.Test(i) {
-			p.translate(i, whites)
+		for x := 0; x < size; x++ {
+
+			switch row[x] {
+			case 'X':
+				p.SetBlack(x, y)
+			case 'O':
+				p.SetWhite(x, y)
+			case '#':
+				p.SetBlack(x, y)
+				p.SetWhite(x, y)
+			case '.':
+			default:
+				return Pattern{}, fmt.Errorf("hash: unknown cell %q at row %d column %d", row[x], y+1, x+1)
+			}
 		}
 	}
 
-	// Reverse color
-	for i := 8; i < 16; i++ {
-
-		blacks[i] = whites[i-8]
-
-		whites[i] = blacks[i-8]
-	}
+	return p, nil
+}
 
-	// Find canonical
-	bc := p.black
-	wc := p.white
+// diagramRows returns the non-blank trimmed lines of a diagram, checking
+// there is an odd number of them.
+func diagramRows(s string) ([]string, error) {
 
-	bwc := p.combineBitSet(bc, wc)
+	rows := diagramLines(s)
 
-	for j := 1; j < 16; j++ {
+	if len(rows) == 0 {
+		return nil, errors.New("hash: empty pattern")
+	}
 
-		a := p.combineBitSet(blacks[j], whites[j])
+	if len(rows)%2 == 0 {
+		return nil, fmt.Errorf("hash: pattern size %d is not odd", len(rows))
+	}
 
-		b := bwc.Clone()
+	return rows, nil
+}
 
-		// Find different bits.
-		b = b.SymmetricDifference(a)
+// diagramLines returns the non-blank trimmed lines of a diagram.
+func diagramLines(s string) []string {
 
-		// Check first true bit.
-		n := p.nextSetBit(0, b)
-		if n > 0 {
+	var rows []string
 
-			if bwc.Test(uint(n)) == false {
+	for _, line := range strings.Split(s, "\n") {
 
-				bc = blacks[j]
-				wc = whites[j]
+		line = strings.TrimSpace(line)
 
-				bwc = a
-			}
+		if line != "" {
+			rows = append(rows, line)
 		}
 	}
 
-	return bc, wc
+	return rows
 }
 
-func (p *Pattern) initBitSet(bitSet *bitset.BitSet, length uint) []*bitset.BitSet {
-
-	result := []*bitset.BitSet{
-		bitSet, // 0
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length), // 7
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length),
-		bitset.New(length), //15
-	}
+// Size returns the number of cells on each side of the pattern.
+func (p *Pattern) Size() int {
 
-	return result
+	return p.size
 }
 
-func (p *Pattern) translate(i uint, bitSet []*bitset.BitSet) {
-
-	// Define transformation matices.
-	t := []int{-p.size / 2, -p.size / 2}
-	tInv := []int{p.size / 2, p.size / 2}
-
-	// Transform coordinate.
-	x := int(i) / p.size
-	y := int(i) % p.size
-
-	for j := 1; j < 8; j++ {
-
-		// Translation
-		xt := x + t[0]
-		yt := y + t[1]
-
-		// Rotation
-		xr := xt
-		yr := yt
-
-		switch j {
+func (p *Pattern) SetBlack(x, y int) {
 
-		case 1:
-			xr = rot90[0]*xt + rot90[1]*yt
-			yr = rot90[2]*xt + rot90[3]*yt
+	p.black.set(y*p.size + x)
+}
 
-		case 2:
-			xr = rot180[0]*xt + rot180[1]*yt
-			yr = rot180[2]*xt + rot180[3]*yt
+func (p *Pattern) SetWhite(x, y int) {
 
-		case 3:
-			xr = rot270[0]*xt + rot270[1]*yt
-			yr = rot270[2]*xt + rot270[3]*yt
+	p.white.set(y*p.size + x)
+}
 
-		case 5:
-			xr = rot90[0]*xt + rot90[1]*yt
-			yr = rot90[2]*xt + rot90[3]*yt
+// A Symmetry is one of the 8 geometric transforms of a square pattern.
+// Symmetries 0 to 3 rotate by 0, 90, 180 and 270 degrees, symmetries 4 to 7
+// rotate the same way and then mirror horizontally.
+type Symmetry int
 
-		case 6:
-			xr = rot180[0]*xt + rot180[1]*yt
-			yr = rot180[2]*xt + rot180[3]*yt
+// Apply maps a coordinate of a pattern to the transformed pattern.
+func (s Symmetry) Apply(x, y, size int) (int, int) {
 
-		case 7:
-			xr = rot270[0]*xt + rot270[1]*yt
-			yr = rot270[2]*xt + rot270[3]*yt
-		}
+	for i := 0; i < int(s)%4; i++ {
+		x, y = y, size-1-x
+	}
 
-		// Horizontal mirror.
-		xh := xr
-		yh := yr
+	if s >= 4 {
+		x = size - 1 - x
+	}
 
-		if j > 3 {
-			xh = mirror[0]*xr + mirror[1]*yr
-			yh = mirror[2]*xr + mirror[3]*yr
-		}
+	return x, y
+}
 
-		// Translation inverse.
-		xt = xh + tInv[0]
-		yt = yh + tInv[1]
+// Inverse returns the symmetry which maps coordinates back.
+func (s Symmetry) Inverse() Symmetry {
 
-		// set bit.
-		q := uint(xt*p.size + yt)
-		bitSet[j].Set(q)
+	if s >= 4 {
+		return s
 	}
-}
-
-func (p *Pattern) combineBitSet(b1 *bitset.BitSet, b2 *bitset.BitSet) *bitset.BitSet {
 
-	return bitset.From(append(b2.Bytes(), b1.Bytes()...))
+	return (4 - s) % 4
 }
 
-func (p *Pattern) nextSetBit(index int, b *bitset.BitSet) int {
+// Canonical returns the pattern whose hash GetHash computes, the symmetry
+// which maps this pattern onto it and whether its colors were swapped.
+// Map a coordinate of the canonical pattern back with sym.Inverse().Apply.
+func (p *Pattern) Canonical() (c Pattern, sym Symmetry, swapped bool) {
 
-	r := -1
+	t := p.newTransformer()
+	j := t.legacy()
 
-	for i := index; i < p.size*p.size; i++ {
-		if b.Test(uint(i)) {
-			r = int(i)
-			break
-		}
+	c = Pattern{
+		size:  p.size,
+		black: t.bestBlack,
+		white: t.bestWhite,
 	}
 
-	return r
+	return c, Symmetry(j % 8), j >= 8
 }
 
+// GetHash is the canonical hash value of the pattern. Values of odd sizes are
+// those of the first version of this package. Values of even sizes changed
+// when the transforms of even patterns were made symmetries of the square;
+// the first version moved cells off the pattern.
 func (p *Pattern) GetHash() uint64 {
 
-	bc, wc := p.canonical()
+	t := p.newTransformer()
+	t.legacy()
 
-	b := bc.Bytes()
-	w := wc.Bytes()
+	return hashWords(p.size, &t.bestBlack, &t.bestWhite)
+}
+
+// hashWords hashes the words used by a size by size pattern, the words of
+// the bit sets GetHash was first defined on.
+func hashWords(size int, b, w *bitBoard) uint64 {
 
 	r := uint64(1)
+	n := numWords(size)
 
-	for i := 0; i < len(b); i++ {
-		r = hash(r, b[i])
+	for _, v := range b[:n] {
+		r = hash(r, v)
 	}
 
-	for i := 0; i < len(w); i++ {
-		r = hash(r, w[i])
+	for _, v := range w[:n] {
+		r = hash(r, v)
 	}
 
-	r = hash(r, uint64(p.size))
+	r = hash(r, uint64(size))
 
 	return r
 }
@@ -267,29 +250,23 @@ func hash(r, v uint64) uint64 {
 	return (prime*r + v%wordLimit) % celing
 }
 
-func (p *Pattern) string(b1 *bitset.BitSet, b2 *bitset.BitSet) string {
+// String is the text diagram of the pattern, the notation of ParsePattern.
+func (p *Pattern) String() string {
 
-	var r string
+	return p.string(&p.black, &p.white)
+}
 
-	l := uint(p.size * p.size)
+func (p *Pattern) string(b1, b2 *bitBoard) string {
 
-	for i := uint(0); i < l; i++ {
+	var r string
 
-		var c string
+	l := p.size * p.size
 
-		if b1.Test(i) && b2.Test(i) {
-			c = "#"
-		} else if b1.Test(i) {
-			c = "X"
-		} else if b2.Test(i) {
-			c = "O"
-		} else {
-			c = "."
-		}
+	for i := 0; i < l; i++ {
 
-		r += c
+		r += string(".XO#"[cell(b1, b2, i)])
 
-		if (i+1)%uint(p.size) == 0 && i != 0 {
+		if (i+1)%p.size == 0 && i != 0 {
 			r += "\n"
 		}
 	}
@@ -0,0 +1,78 @@
+package hash
+
+import (
+	"math/bits"
+)
+
+// GetHash64 is a canonical hash value with the same equivalence as GetHash,
+// mirrored, rotated and color reversed patterns are equal, but it uses all
+// 64 bits and compares every cell when choosing the canonical form.
+//
+// It is a Zobrist hash: the exclusive or of a key for the size and a key for
+// each non-empty cell. Keys are derived from splitmix64 by a fixed formula,
+// so values are stable across versions and may be stored.
+func (p *Pattern) GetHash64() uint64 {
+
+	t := p.newTransformer()
+	t.strict(16)
+
+	return zobrist(p.size, &t.bestBlack, &t.bestWhite)
+}
+
+// GetSymmetricHash64 is GetHash64 with the equivalence of GetSymmetricHash,
+// color reversed patterns are different.
+func (p *Pattern) GetSymmetricHash64() uint64 {
+
+	t := p.newTransformer()
+	t.strict(8)
+
+	return zobrist(p.size, &t.bestBlack, &t.bestWhite)
+}
+
+// GetRelativeHash64 is GetSymmetricHash64 with colors relative to the player
+// to move, so the player to move is always black.
+func (p *Pattern) GetRelativeHash64(blackToPlay bool) uint64 {
+
+	t := p.newTransformer()
+
+	if !blackToPlay {
+		t.reverse()
+	}
+
+	t.strict(8)
+
+	return zobrist(p.size, &t.bestBlack, &t.bestWhite)
+}
+
+func zobrist(size int, b, w *bitBoard) uint64 {
+
+	r := ZobristKey(uint64(size) << 34)
+
+	for i := 0; i < numWords(size); i++ {
+
+		v := b[i] | w[i]
+
+		for v != 0 {
+
+			j := i*64 + bits.TrailingZeros64(v)
+			v &= v - 1
+
+			r ^= ZobristKey(uint64(size)<<34 | uint64(j)<<2 | uint64(cell(b, w, j)))
+		}
+	}
+
+	return r
+}
+
+// ZobristKey is the splitmix64 finalizer applied to a seeded input, the key
+// of the hashes of this package and of board.Board. Neither the seed nor the
+// formula may change.
+func ZobristKey(v uint64) uint64 {
+
+	z := v + 0x9e3779b97f4a7c15
+
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+
+	return z ^ z>>31
+}
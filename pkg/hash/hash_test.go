@@ -1,19 +1,59 @@
 package hash
 
 import (
-	"github.com/willf/bitset"
+	"fmt"
+	"math/rand"
 	"testing"
 )
 
-func TestUnion(t *testing.T) {
+func TestBitBoard(t *testing.T) {
 
-	a := bitset.From([]uint64{0, 1})
-	b := bitset.From([]uint64{1})
+	rnd := rand.New(rand.NewSource(1))
 
-	a.Union(b)
+	for _, size := range []int{1, 3, 8, 9, 19, MaxSize} {
 
-	if len(a.Bytes()) != 2 {
-		t.Error("Union should not modify current object")
+		var b bitBoard
+
+		for i := 0; i < size*size; i++ {
+			if rnd.Intn(3) == 0 {
+				b.set(i)
+			}
+		}
+
+		for y := 0; y < size; y++ {
+
+			var r bitBoard
+			r.orRow(y, size, b.row(y, size))
+
+			for x := 0; x < size; x++ {
+				if r.test(y*size+x) != b.test(y*size+x) {
+					t.Fatalf("size %d: row %d differs at %d", size, y, x)
+				}
+			}
+		}
+
+		for s := Symmetry(0); s < 8; s++ {
+
+			var r bitBoard
+			r.transform(&b, s, size)
+
+			for y := 0; y < size; y++ {
+				for x := 0; x < size; x++ {
+
+					tx, ty := s.Apply(x, y, size)
+
+					if r.test(ty*size+tx) != b.test(y*size+x) {
+						t.Fatalf("size %d: symmetry %d moves (%d, %d) wrong", size, s, x, y)
+					}
+				}
+			}
+
+			for i := size * size; i < maxWords*64; i++ {
+				if r.test(i) {
+					t.Fatalf("size %d: symmetry %d sets bit %d", size, s, i)
+				}
+			}
+		}
 	}
 }
 
@@ -62,8 +102,59 @@ func TestCanonical(t *testing.T) {
 	}
 }
 
+// TestGetHashGolden guards the values of GetHash. Odd sizes match the first
+// version of the package; even sizes do not, see GetHash. A point both black
+// and white is an edge.
+func TestGetHashGolden(t *testing.T) {
+
+	cases := map[string]struct {
+		size     int
+		black    [][2]int
+		white    [][2]int
+		expected uint64
+	}{
+		"2": {2, [][2]int{{0, 0}}, [][2]int{{1, 0}}, 2394},
+		"3": {3, [][2]int{{0, 0}, {2, 1}}, [][2]int{{1, 1}, {2, 2}}, 6438},
+		"4": {4, [][2]int{{0, 1}, {3, 3}}, [][2]int{{2, 0}, {1, 2}, {3, 3}}, 782643},
+		"5": {5, [][2]int{{3, 0}, {2, 1}, {2, 2}, {1, 4}, {0, 4}}, [][2]int{{4, 2}, {3, 3}, {0, 4}}, 3173810996},
+		"7": {7, [][2]int{{1, 1}, {2, 5}, {6, 0}}, [][2]int{{3, 3}, {4, 1}, {0, 6}, {6, 0}}, 745059402484508},
+	}
+
+	for k, tc := range cases {
+
+		p := NewPattern(tc.size)
+
+		for _, c := range tc.black {
+			p.SetBlack(c[0], c[1])
+		}
+
+		for _, c := range tc.white {
+			p.SetWhite(c[0], c[1])
+		}
+
+		if h := p.GetHash(); h != tc.expected {
+			t.Errorf("%s: hash %v, expected %v", k, h, tc.expected)
+		}
+	}
+}
+
 var result uint64
 
+// benchPattern has a few stones near the centre of a size by size pattern.
+func benchPattern(size int) Pattern {
+
+	p := NewPattern(size)
+
+	c := size / 2
+
+	p.SetBlack(c, c-1)
+	p.SetBlack(c-1, c)
+	p.SetWhite(c+1, c)
+	p.SetWhite(c, c+1)
+
+	return p
+}
+
 func BenchmarkGetHash(b *testing.B) {
 
 	var h uint64
@@ -76,9 +167,750 @@ func BenchmarkGetHash(b *testing.B) {
 	p.SetWhite(4, 2)
 	p.SetWhite(3, 3)
 
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		h = p.GetHash()
+	}
+
+	result = h
+}
+
+func benchmarkGetHash(b *testing.B, size int) {
+
+	var h uint64
+
+	p := benchPattern(size)
+
+	b.ReportAllocs()
+
 	for n := 0; n < b.N; n++ {
 		h = p.GetHash()
 	}
 
 	result = h
 }
+
+func BenchmarkGetHash3x3(b *testing.B)   { benchmarkGetHash(b, 3) }
+func BenchmarkGetHash5x5(b *testing.B)   { benchmarkGetHash(b, 5) }
+func BenchmarkGetHash9x9(b *testing.B)   { benchmarkGetHash(b, 9) }
+func BenchmarkGetHash19x19(b *testing.B) { benchmarkGetHash(b, 19) }
+
+func benchmarkGetHash64(b *testing.B, size int) {
+
+	var h uint64
+
+	p := benchPattern(size)
+
+	b.ReportAllocs()
+
+	for n := 0; n < b.N; n++ {
+		h = p.GetHash64()
+	}
+
+	result = h
+}
+
+func BenchmarkGetHash64_3x3(b *testing.B)   { benchmarkGetHash64(b, 3) }
+func BenchmarkGetHash64_5x5(b *testing.B)   { benchmarkGetHash64(b, 5) }
+func BenchmarkGetHash64_9x9(b *testing.B)   { benchmarkGetHash64(b, 9) }
+func BenchmarkGetHash64_19x19(b *testing.B) { benchmarkGetHash64(b, 19) }
+
+func TestHashAllocations(t *testing.T) {
+
+	for _, size := range []int{3, 5, 9, 19} {
+
+		p := benchPattern(size)
+
+		cases := map[string]func(){
+			"GetHash":            func() { result = p.GetHash() },
+			"GetSymmetricHash":   func() { result = p.GetSymmetricHash() },
+			"GetRelativeHash":    func() { result = p.GetRelativeHash(false) },
+			"GetHash64":          func() { result = p.GetHash64() },
+			"GetSymmetricHash64": func() { result = p.GetSymmetricHash64() },
+			"GetRelativeHash64":  func() { result = p.GetRelativeHash64(false) },
+		}
+
+		for k, f := range cases {
+			if n := testing.AllocsPerRun(100, f); n != 0 {
+				t.Errorf("%s %dx%d: %v allocations", k, size, size, n)
+			}
+		}
+	}
+}
+
+func TestParsePattern(t *testing.T) {
+
+	diagram := `
+	.#..O
+	.#.X.
+	.#.X.
+	.####
+	.....
+	`
+
+	p, err := ParsePattern(diagram)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if p.Size() != 5 {
+		t.Errorf("size %d, expected 5", p.Size())
+	}
+
+	expected := ".#..O\n.#.X.\n.#.X.\n.####\n.....\n"
+	if actual := p.String(); actual != expected {
+		t.Errorf("\n actual\n%s\n expected\n%s", actual, expected)
+	}
+
+	q := NewPattern(5)
+
+	q.SetBlack(3, 1)
+	q.SetWhite(4, 0)
+
+	for y := 0; y < 4; y++ {
+		q.SetBlack(1, y)
+		q.SetWhite(1, y)
+	}
+
+	for x := 2; x < 5; x++ {
+		q.SetBlack(x, 3)
+		q.SetWhite(x, 3)
+	}
+
+	q.SetBlack(3, 2)
+
+	if p.GetHash() != q.GetHash() {
+		t.Errorf("parsed hash %v, expected %v", p.GetHash(), q.GetHash())
+	}
+}
+
+func TestParsePatternError(t *testing.T) {
+
+	cases := map[string]string{
+		"empty":     "\n\n",
+		"even":      "..\n..",
+		"short row": "...\n..\n...",
+		"bad cell":  "...\n.Z.\n...",
+		"long row":  "...\n...\n....",
+	}
+
+	for k, tc := range cases {
+		if _, err := ParsePattern(tc); err == nil {
+			t.Errorf("%s: parsed without error", k)
+		}
+	}
+}
+
+func TestPatternEncoding(t *testing.T) {
+
+	p, err := ParsePattern(`
+	.#..O
+	.#.X.
+	.#.X.
+	.####
+	.....
+	`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	b, _ := p.MarshalBinary()
+
+	// Size and 25 cells of 2 bits, row 0 is . # . . O and row 1 starts . # .
+	if len(b) != 8 || b[0] != 5 || b[1] != 0x0c || b[2] != 0x32 {
+		t.Errorf("binary encoding % x", b)
+	}
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for _, size := range []int{0, 1, 2, 3, 5, 8, 19, MaxSize} {
+
+		p := NewPattern(size)
+
+		for i := 0; i < size*size; i++ {
+
+			c := rnd.Intn(4)
+
+			if c&1 != 0 {
+				p.SetBlack(i%size, i/size)
+			}
+
+			if c&2 != 0 {
+				p.SetWhite(i%size, i/size)
+			}
+		}
+
+		var q Pattern
+
+		b, _ := p.MarshalBinary()
+		if err := q.UnmarshalBinary(b); err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+
+		if q.String() != p.String() || q.GetHash() != p.GetHash() {
+			t.Errorf("size %d: binary decoded\n%s\nexpected\n%s", size, q.String(), p.String())
+		}
+
+		var r Pattern
+
+		text, _ := p.MarshalText()
+		if err := r.UnmarshalText(text); err != nil {
+			t.Fatalf("size %d: %v", size, err)
+		}
+
+		if r.Size() != size || r.String() != p.String() {
+			t.Errorf("size %d: text decoded\n%s\nexpected\n%s", size, r.String(), p.String())
+		}
+	}
+}
+
+func TestPatternEncodingError(t *testing.T) {
+
+	cases := map[string][]byte{
+		"empty":         {},
+		"too large":     {26},
+		"short":         {3, 0, 0},
+		"long":          {1, 0, 0},
+		"trailing bits": {1, 0x04},
+	}
+
+	for k, tc := range cases {
+
+		var p Pattern
+
+		if err := p.UnmarshalBinary(tc); err == nil {
+			t.Errorf("%s: decoded without error", k)
+		}
+	}
+
+	var p Pattern
+
+	if err := p.UnmarshalText([]byte("..\n.Z")); err == nil {
+		t.Error("text with an unknown cell decoded without error")
+	}
+}
+
+func TestCanonicalSymmetry(t *testing.T) {
+
+	p, err := ParsePattern(`
+	...X.
+	..X..
+	..X.O
+	...O.
+	.....
+	`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	c, sym, swapped := p.Canonical()
+
+	if c.GetHash() != p.GetHash() {
+		t.Errorf("canonical hash %v, expected %v", c.GetHash(), p.GetHash())
+	}
+
+	// Map every cell of the pattern and compare with the canonical pattern.
+	q := NewPattern(5)
+
+	for y := 0; y < 5; y++ {
+		for x := 0; x < 5; x++ {
+
+			b := p.black.test(y*5 + x)
+			w := p.white.test(y*5 + x)
+
+			if swapped {
+				b, w = w, b
+			}
+
+			tx, ty := sym.Apply(x, y, 5)
+
+			if b {
+				q.SetBlack(tx, ty)
+			}
+
+			if w {
+				q.SetWhite(tx, ty)
+			}
+		}
+	}
+
+	if q.String() != c.String() {
+		t.Errorf("symmetry %d swapped %v\n mapped\n%s\n canonical\n%s", sym, swapped, q.String(), c.String())
+	}
+
+	for s := Symmetry(0); s < 8; s++ {
+
+		x, y := s.Apply(1, 0, 5)
+		ix, iy := s.Inverse().Apply(x, y, 5)
+
+		if ix != 1 || iy != 0 {
+			t.Errorf("symmetry %d: (1, 0) -> (%d, %d) -> (%d, %d)", s, x, y, ix, iy)
+		}
+	}
+}
+
+func TestSymmetricHash(t *testing.T) {
+
+	p, _ := ParsePattern(`
+	.X.
+	...
+	O..
+	`)
+
+	// Rotated a quarter turn.
+	q, _ := ParsePattern(`
+	O..
+	..X
+	...
+	`)
+
+	r := p.Reversed()
+
+	if p.GetSymmetricHash() != q.GetSymmetricHash() {
+		t.Errorf("rotated symmetric hash %v, expected %v", q.GetSymmetricHash(), p.GetSymmetricHash())
+	}
+
+	if p.GetSymmetricHash() == r.GetSymmetricHash() {
+		t.Error("reversed pattern has the same symmetric hash")
+	}
+
+	if p.GetRelativeHash(true) != r.GetRelativeHash(false) {
+		t.Error("relative hash depends on the color to play")
+	}
+
+	c, sym := q.CanonicalSymmetric()
+
+	if c.GetSymmetricHash() != q.GetSymmetricHash() {
+		t.Errorf("canonical symmetric hash %v, expected %v", c.GetSymmetricHash(), q.GetSymmetricHash())
+	}
+
+	x, y := sym.Apply(0, 0, 3)
+	if !c.white.test(y*3 + x) {
+		t.Errorf("symmetry %d maps white (0, 0) to (%d, %d)\n%s", sym, x, y, c.String())
+	}
+}
+
+// Stored hash values depend on these never changing.
+func TestHash64Stable(t *testing.T) {
+
+	p, _ := ParsePattern(`
+	...X.
+	..X..
+	..X.O
+	...O.
+	.....
+	`)
+
+	e := NewPattern(3)
+
+	cases := map[string]struct {
+		actual   uint64
+		expected uint64
+	}{
+		"empty":     {e.GetHash64(), 0x7c7905bd16929e77},
+		"hash":      {p.GetHash64(), 0x6f440aea1e1e4b6d},
+		"symmetric": {p.GetSymmetricHash64(), 0x6f440aea1e1e4b6d},
+		"relative":  {p.GetRelativeHash64(false), 0x54ad7d6c2a815d9e},
+	}
+
+	for k, tc := range cases {
+		if tc.actual != tc.expected {
+			t.Errorf("%s: %#x, expected %#x", k, tc.actual, tc.expected)
+		}
+	}
+}
+
+func TestHash64Collision(t *testing.T) {
+
+	if testing.Short() {
+		t.Skip("enumerates all 3x3 patterns")
+	}
+
+	// Canonical diagram of each hash value.
+	seen := map[uint64]string{}
+
+	for code := 0; code < 1<<18; code++ {
+
+		p := NewPattern(3)
+
+		for i := 0; i < 9; i++ {
+
+			c := code >> uint(2*i) & 3
+
+			if c&1 != 0 {
+				p.SetBlack(i%3, i/3)
+			}
+
+			if c&2 != 0 {
+				p.SetWhite(i%3, i/3)
+			}
+		}
+
+		tr := p.newTransformer()
+		tr.strict(16)
+
+		diagram := fmt.Sprint(tr.bestBlack, tr.bestWhite)
+
+		h := p.GetHash64()
+
+		if d, ok := seen[h]; ok && d != diagram {
+			t.Fatalf("%#x is the hash of\n%s\n and\n%s", h, d, diagram)
+		}
+
+		seen[h] = diagram
+	}
+
+	t.Logf("%d distinct 3x3 patterns", len(seen))
+}
+
+func TestMatchPattern(t *testing.T) {
+
+	// White above and black left of an empty centre.
+	m, err := CompileMatchPattern(`
+	?O?
+	X.x
+	?o?
+	`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	cases := map[string]struct {
+		window   string
+		expected bool
+	}{
+		"exact":     {".O.\nX..\n...", true},
+		"wildcards": {"XOO\nX.X\nOOX", true},
+		"rotated":   {".X.\n..O\n...", true},
+		"swapped":   {".X.\nO..\n...", true},
+		"edge":      {"###\nX.X\n...", false},
+		"occupied":  {".O.\nXX.\n...", false},
+		"not white": {".O.\nX.O\n.X.", false},
+	}
+
+	for k, tc := range cases {
+
+		w, err := ParsePattern(tc.window)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		ok, sym, swapped := m.Match(w)
+
+		if ok != tc.expected {
+			t.Errorf("%s: match %v, expected %v", k, ok, tc.expected)
+			continue
+		}
+
+		if !ok {
+			continue
+		}
+
+		// The white stone at (1, 0) must be found where the transform says.
+		x, y := sym.Apply(1, 0, 3)
+
+		c := cell(&w.black, &w.white, y*3+x)
+		if swapped && c != 1 || !swapped && c != 2 {
+			t.Errorf("%s: symmetry %d swapped %v maps (1, 0) to (%d, %d)", k, sym, swapped, x, y)
+		}
+	}
+
+	if _, err := CompileMatchPattern("?.\n.."); err == nil {
+		t.Error("even size compiled without error")
+	}
+
+	if _, err := CompileMatchPattern("...\n.Z.\n..."); err == nil {
+		t.Error("unknown cell compiled without error")
+	}
+}
+
+func TestMatchPatternEdgeWildcard(t *testing.T) {
+
+	m, err := CompileMatchPattern("*O*\nX.x\n???")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	cases := map[string]struct {
+		window   string
+		expected bool
+	}{
+		"board":       {"XOO\nX..\n...", true},
+		"edge on *":   {"#O#\nX..\n...", true},
+		"edge on ?":   {".O.\nX..\n###", false},
+		"edge on all": {"#O#\nX..\n###", false},
+	}
+
+	for k, tc := range cases {
+
+		w, err := ParsePattern(tc.window)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		if ok, _, _ := m.Match(w); ok != tc.expected {
+			t.Errorf("%s: match %v, expected %v", k, ok, tc.expected)
+		}
+	}
+}
+
+func TestTable3x3(t *testing.T) {
+
+	tb := NewTable3x3()
+
+	// Burnside: (4^8 + 2*4^2 + 4^4 + 4*4^5) / 8 orbits of 8 neighbors.
+	if tb.NumIDs() != 8740 {
+		t.Errorf("%d IDs, expected 8740", tb.NumIDs())
+	}
+
+	p, _ := ParsePattern(`
+	.X.
+	..O
+	O..
+	`)
+
+	// Mirrored.
+	q, _ := ParsePattern(`
+	.X.
+	O..
+	..O
+	`)
+
+	code := Code3x3(p)
+
+	if r := Pattern3x3(code); r.String() != p.String() {
+		t.Errorf("\n%s\n expected\n%s", r.String(), p.String())
+	}
+
+	if tb.ID(code) != tb.ID(Code3x3(q)) {
+		t.Error("mirrored codes have different IDs")
+	}
+
+	r := p.Reversed()
+	swapped := SwapColors3x3(code)
+
+	if swapped != Code3x3(r) {
+		t.Errorf("swapped code %#x, expected %#x", swapped, Code3x3(r))
+	}
+
+	if tb.ID(code) == tb.ID(swapped) {
+		t.Error("color reversed codes have the same ID")
+	}
+
+	m, _ := CompileMatchPattern(`
+	?X?
+	?.O
+	???
+	`)
+
+	if n := tb.SetMatchWeight(&m, 5); n == 0 {
+		t.Error("no code matched")
+	}
+
+	if tb.Weight(Code3x3(q)) != 5 || tb.Weight(0) != 1 {
+		t.Errorf("weights %v and %v, expected 5 and 1", tb.Weight(Code3x3(q)), tb.Weight(0))
+	}
+}
+
+func TestShape(t *testing.T) {
+
+	cases := map[string]struct {
+		shape    *Shape
+		diagram  string
+		cells    int
+		symmetry int
+	}{
+		"diamond": {Diamond(2), "..X..\n.XXX.\nXXXXX\n.XXX.\n..X..\n", 13, 8},
+		"circle":  {Circle(2), ".XXX.\nXXXXX\nXXXXX\nXXXXX\n.XXX.\n", 21, 8},
+		"rect":    {Rect(5, 3), "XXXXX\nXXXXX\nXXXXX\n", 15, 4},
+		"even":    {Rect(2, 2), "XX\nXX\n", 4, 8},
+		"mixed":   {Rect(4, 3), "XXXX\nXXXX\nXXXX\n", 12, 4},
+		"tall":    {Rect(3, 4), "XXX\nXXX\nXXX\nXXX\n", 12, 4},
+		"domino":  {Rect(2, 1), "XX\n", 2, 4},
+	}
+
+	for k, tc := range cases {
+
+		if s := tc.shape.String(); s != tc.diagram {
+			t.Errorf("%s:\n%s expected\n%s", k, s, tc.diagram)
+		}
+
+		if n := tc.shape.NumCells(); n != tc.cells {
+			t.Errorf("%s: %d cells, expected %d", k, n, tc.cells)
+		}
+
+		if n := len(tc.shape.Group()); n != tc.symmetry {
+			t.Errorf("%s: %d symmetries, expected %d", k, n, tc.symmetry)
+		}
+	}
+
+	family := DiamondFamily(4)
+
+	for i := 1; i < len(family); i++ {
+		if !family[i].Contains(family[i-1]) || family[i-1].Contains(family[i]) {
+			t.Errorf("diamond %d does not strictly contain diamond %d", i+1, i)
+		}
+	}
+
+	if !Circle(3).Contains(Diamond(3)) {
+		t.Error("circle 3 does not contain diamond 3")
+	}
+
+	// Half turns and mirrors only, each a permutation of the cells.
+	rect := Rect(4, 3)
+
+	if g := rect.Group(); len(g) != 4 || g[0] != 0 || g[1] != 2 || g[2] != 4 || g[3] != 6 {
+		t.Errorf("rect 4x3 group %v, expected [0 2 4 6]", g)
+	}
+
+	p := NewShapedPattern(rect)
+	empty := p.GetSymmetricHash64()
+
+	p.SetBlack(3, 2)
+
+	if p.GetSymmetricHash64() == empty {
+		t.Error("a stone in the corner of rect 4x3 hashes like the empty pattern")
+	}
+}
+
+// Every transform of a random pattern has the same canonical hash.
+func TestTransformsHashEqual(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	shapes := []*Shape{Diamond(3), Circle(3), Rect(5, 5), Rect(7, 3), Rect(4, 4), Rect(4, 3), Rect(2, 1)}
+
+	for _, s := range shapes {
+		for n := 0; n < 50; n++ {
+
+			p := NewShapedPattern(s)
+
+			for x := 0; x < s.Width(); x++ {
+				for y := 0; y < s.Height(); y++ {
+					p.set(x, y, uint8(rnd.Intn(4)))
+				}
+			}
+
+			h := p.GetHash64()
+
+			for _, sym := range s.Group() {
+				for _, swap := range []bool{false, true} {
+
+					q, err := p.Transform(sym, swap)
+					if err != nil {
+						t.Fatal(err.Error())
+					}
+
+					if q.GetHash64() != h {
+						t.Fatalf("symmetry %d swap %v changes the hash of\n%s", sym, swap, p.String())
+					}
+
+					if !swap && q.GetSymmetricHash64() != p.GetSymmetricHash64() {
+						t.Fatalf("symmetry %d changes the symmetric hash of\n%s", sym, p.String())
+					}
+				}
+			}
+		}
+	}
+
+	for n := 0; n < 50; n++ {
+
+		p := NewPattern(5)
+
+		for i := 0; i < 25; i++ {
+
+			c := rnd.Intn(4)
+
+			if c&1 != 0 {
+				p.SetBlack(i%5, i/5)
+			}
+
+			if c&2 != 0 {
+				p.SetWhite(i%5, i/5)
+			}
+		}
+
+		h := p.GetHash64()
+
+		for j := 0; j < 16; j++ {
+
+			q := NewPattern(5)
+
+			for i := 0; i < 25; i++ {
+
+				b := p.black.test(i)
+				w := p.white.test(i)
+
+				if j >= 8 {
+					b, w = w, b
+				}
+
+				x, y := Symmetry(j%8).Apply(i%5, i/5, 5)
+
+				if b {
+					q.SetBlack(x, y)
+				}
+
+				if w {
+					q.SetWhite(x, y)
+				}
+			}
+
+			if q.GetHash64() != h {
+				t.Fatalf("transform %d changes the hash of\n%s", j, p.String())
+			}
+		}
+	}
+}
+
+// Pattern.Transform moves each stone like Symmetry.Apply.
+func TestPatternTransform(t *testing.T) {
+
+	rnd := rand.New(rand.NewSource(1))
+
+	for n := 0; n < 50; n++ {
+
+		p := NewPattern(5)
+
+		for i := 0; i < 25; i++ {
+			switch rnd.Intn(3) {
+			case 1:
+				p.SetBlack(i%5, i/5)
+			case 2:
+				p.SetWhite(i%5, i/5)
+			}
+		}
+
+		for j := 0; j < 16; j++ {
+
+			sym, swapped := Symmetry(j%8), j >= 8
+
+			q := NewPattern(5)
+
+			for i := 0; i < 25; i++ {
+
+				b := p.black.test(i)
+				w := p.white.test(i)
+
+				if swapped {
+					b, w = w, b
+				}
+
+				x, y := sym.Apply(i%5, i/5, 5)
+
+				if b {
+					q.SetBlack(x, y)
+				}
+
+				if w {
+					q.SetWhite(x, y)
+				}
+			}
+
+			if p.Transform(sym, swapped) != q {
+				t.Fatalf("Transform %d differs from Symmetry.Apply for\n%s", j, p.String())
+			}
+		}
+	}
+}
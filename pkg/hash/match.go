@@ -0,0 +1,202 @@
+package hash
+
+import (
+	"fmt"
+)
+
+// A Cell is the set of contents a pattern cell accepts.
+type Cell uint8
+
+// Cell contents.
+const (
+	Empty Cell = 1 << iota
+	Black
+	White
+	Edge
+
+	// Any accepts every point on the board.
+	Any = Empty | Black | White
+)
+
+// cells maps the diagram notation to cells.
+var cells = map[byte]Cell{
+	'.': Empty,
+	'X': Black,
+	'O': White,
+	'#': Edge,
+	'?': Any,
+	'*': Any | Edge,
+	'x': Empty | Black,
+	'o': Empty | White,
+}
+
+// swap returns the cell with black and white exchanged.
+func (c Cell) swap() Cell {
+
+	return c&^(Black|White) | (c&Black)<<1 | (c&White)>>1
+}
+
+// A MatchPattern is a pattern whose cells accept sets of contents, in the
+// style of GNU Go pattern libraries.
+type MatchPattern struct {
+	size  int
+	cells []Cell
+
+	// Constraints in window coordinates for each distinct transform.
+	variants []variant
+}
+
+type variant struct {
+	sym     Symmetry
+	swapped bool
+	cells   []Cell
+}
+
+// CompileMatchPattern creates a MatchPattern from a diagram in the notation
+// of ParsePattern extended with wildcards.
+//
+//	.  empty
+//	X  black
+//	O  white
+//	#  edge
+//	?  empty, black or white
+//	*  anything, edge included
+//	x  empty or black
+//	o  empty or white
+func CompileMatchPattern(s string) (MatchPattern, error) {
+
+	rows, err := diagramRows(s)
+	if err != nil {
+		return MatchPattern{}, err
+	}
+
+	size := len(rows)
+
+	m := MatchPattern{
+		size:  size,
+		cells: make([]Cell, size*size),
+	}
+
+	for y, row := range rows {
+
+		if len(row) != size {
+			return MatchPattern{}, fmt.Errorf("hash: row %d has %d cells, expected %d", y+1, len(row), size)
+		}
+
+		for x := 0; x < size; x++ {
+
+			c, ok := cells[row[x]]
+			if !ok {
+				return MatchPattern{}, fmt.Errorf("hash: unknown cell %q at row %d column %d", row[x], y+1, x+1)
+			}
+
+			m.cells[y*size+x] = c
+		}
+	}
+
+	m.compile()
+
+	return m, nil
+}
+
+// Size returns the number of cells on each side of the pattern.
+func (m *MatchPattern) Size() int {
+
+	return m.size
+}
+
+// compile builds the constraints of the 16 transforms, skipping duplicates.
+func (m *MatchPattern) compile() {
+
+	for j := 0; j < 16; j++ {
+
+		v := variant{
+			sym:     Symmetry(j % 8),
+			swapped: j >= 8,
+			cells:   make([]Cell, len(m.cells)),
+		}
+
+		for y := 0; y < m.size; y++ {
+			for x := 0; x < m.size; x++ {
+
+				c := m.cells[y*m.size+x]
+				if v.swapped {
+					c = c.swap()
+				}
+
+				tx, ty := v.sym.Apply(x, y, m.size)
+
+				v.cells[ty*m.size+tx] = c
+			}
+		}
+
+		if !m.hasVariant(v.cells) {
+			m.variants = append(m.variants, v)
+		}
+	}
+}
+
+func (m *MatchPattern) hasVariant(cells []Cell) bool {
+
+	for _, v := range m.variants {
+
+		equal := true
+
+		for i := range cells {
+			if v.cells[i] != cells[i] {
+				equal = false
+				break
+			}
+		}
+
+		if equal {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Match reports whether a window of the same size matches the pattern under
+// any symmetry, with or without colors swapped. sym and swapped give the
+// first transform found: cell (x, y) of the pattern, with colors swapped if
+// swapped is true, constrains cell sym.Apply(x, y, size) of the window.
+func (m *MatchPattern) Match(window Pattern) (ok bool, sym Symmetry, swapped bool) {
+
+	if window.size != m.size {
+		return false, 0, false
+	}
+
+	for _, v := range m.variants {
+		if m.matchVariant(&window, v.cells) {
+			return true, v.sym, v.swapped
+		}
+	}
+
+	return false, 0, false
+}
+
+func (m *MatchPattern) matchVariant(window *Pattern, cells []Cell) bool {
+
+	for i, c := range cells {
+
+		var content Cell
+
+		switch cell(&window.black, &window.white, i) {
+		case 0:
+			content = Empty
+		case 1:
+			content = Black
+		case 2:
+			content = White
+		default:
+			content = Edge
+		}
+
+		if c&content == 0 {
+			return false
+		}
+	}
+
+	return true
+}
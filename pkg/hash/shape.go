@@ -0,0 +1,461 @@
+package hash
+
+import (
+	"fmt"
+)
+
+// A Shape is a set of cells inside a width by height box. Cell (x, y) is at
+// index y*width + x of the box, and the centre of the box is the point a
+// pattern is taken around.
+//
+//	Diamond(2)   Circle(2)    Rect(5, 3)
+//	  ..X..       .XXX.       XXXXX
+//	  .XXX.       XXXXX       XXXXX
+//	  XXXXX       XXXXX       XXXXX
+//	  .XXX.       XXXXX
+//	  ..X..       .XXX.
+type Shape struct {
+	width  int
+	height int
+
+	// Box indices of the cells, in increasing order.
+	cells []int
+
+	// Symmetries mapping the shape onto itself, always including 0.
+	group []Symmetry
+
+	// perms[g][i] is the cell index which cell i moves to under group[g].
+	perms [][]int
+
+	key uint64
+}
+
+// Diamond returns the cells within Manhattan distance r of the centre.
+func Diamond(r int) *Shape {
+
+	return newShape(2*r+1, 2*r+1, func(dx, dy int) bool {
+		return abs(dx)+abs(dy) <= r
+	})
+}
+
+// Circle returns the cells (dx, dy) from the centre with dx*dx+dy*dy <= r*r+r,
+// a disc of radius about r+1/2, so Circle(1) is the 3x3 square.
+func Circle(r int) *Shape {
+
+	return newShape(2*r+1, 2*r+1, func(dx, dy int) bool {
+		return dx*dx+dy*dy <= r*r+r
+	})
+}
+
+// Rect returns all cells of a width by height box. Even sides have no
+// centre cell; the box extends further towards positive coordinates.
+func Rect(width, height int) *Shape {
+
+	return newShape(width, height, func(dx, dy int) bool {
+		return true
+	})
+}
+
+// DiamondFamily returns diamonds of radius 1 to n, each containing the last.
+func DiamondFamily(n int) []*Shape {
+
+	r := make([]*Shape, n)
+
+	for i := range r {
+		r[i] = Diamond(i + 1)
+	}
+
+	return r
+}
+
+// CircleFamily returns circles of radius 1 to n, each containing the last.
+func CircleFamily(n int) []*Shape {
+
+	r := make([]*Shape, n)
+
+	for i := range r {
+		r[i] = Circle(i + 1)
+	}
+
+	return r
+}
+
+// newShape builds a shape from a predicate on offsets from the centre cell.
+func newShape(width, height int, in func(dx, dy int) bool) *Shape {
+
+	s := &Shape{
+		width:  width,
+		height: height,
+	}
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			if in(x-(width-1)/2, y-(height-1)/2) {
+				s.cells = append(s.cells, y*width+x)
+			}
+		}
+	}
+
+	s.initGroup()
+
+	s.key = ZobristKey(1<<62 | uint64(width)<<16 | uint64(height))
+	for _, c := range s.cells {
+		s.key = ZobristKey(s.key ^ uint64(c))
+	}
+
+	return s
+}
+
+// initGroup finds the symmetries which map the shape onto itself.
+func (s *Shape) initGroup() {
+
+	index := map[int]int{}
+	for i, c := range s.cells {
+		index[c] = i
+	}
+
+	for sym := Symmetry(0); sym < 8; sym++ {
+
+		// Quarter turns of a box which is not square leave the box.
+		if sym%2 == 1 && s.width != s.height {
+			continue
+		}
+
+		perm := make([]int, len(s.cells))
+		used := make([]bool, len(s.cells))
+		ok := true
+
+		for i, c := range s.cells {
+
+			x, y, inside := s.apply(sym, c%s.width, c/s.width)
+			if !inside {
+				ok = false
+				break
+			}
+
+			// perm must be a permutation of the cells.
+			j, found := index[y*s.width+x]
+			if !found || used[j] {
+				ok = false
+				break
+			}
+
+			perm[i] = j
+			used[j] = true
+		}
+
+		if ok {
+			s.group = append(s.group, sym)
+			s.perms = append(s.perms, perm)
+		}
+	}
+}
+
+// apply maps a box coordinate by a symmetry about the centre of the box.
+// Doubled coordinates keep the centre of even sides an integer.
+func (s *Shape) apply(sym Symmetry, x, y int) (int, int, bool) {
+
+	dx := 2*x - (s.width - 1)
+	dy := 2*y - (s.height - 1)
+
+	for i := 0; i < int(sym)%4; i++ {
+		dx, dy = dy, -dx
+	}
+
+	if sym >= 4 {
+		dx = -dx
+	}
+
+	// An odd doubled coordinate falls between two cells.
+	if (dx+s.width-1)%2 != 0 || (dy+s.height-1)%2 != 0 {
+		return 0, 0, false
+	}
+
+	x = (dx + s.width - 1) / 2
+	y = (dy + s.height - 1) / 2
+
+	return x, y, x >= 0 && x < s.width && y >= 0 && y < s.height
+}
+
+// Width returns the width of the box.
+func (s *Shape) Width() int {
+
+	return s.width
+}
+
+// Height returns the height of the box.
+func (s *Shape) Height() int {
+
+	return s.height
+}
+
+// NumCells returns the number of cells.
+func (s *Shape) NumCells() int {
+
+	return len(s.cells)
+}
+
+// Has reports whether a box coordinate is a cell of the shape.
+func (s *Shape) Has(x, y int) bool {
+
+	return s.cellIndex(x, y) >= 0
+}
+
+// Group returns the symmetries mapping the shape onto itself.
+func (s *Shape) Group() []Symmetry {
+
+	return append([]Symmetry(nil), s.group...)
+}
+
+// Contains reports whether every cell of t, centred on the same point, is a
+// cell of s. Nested families are built from shapes containing each other.
+func (s *Shape) Contains(t *Shape) bool {
+
+	ox := (s.width-1)/2 - (t.width-1)/2
+	oy := (s.height-1)/2 - (t.height-1)/2
+
+	for _, c := range t.cells {
+		if !s.Has(c%t.width+ox, c/t.width+oy) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String draws the shape with X for cells.
+func (s *Shape) String() string {
+
+	var r string
+
+	for y := 0; y < s.height; y++ {
+		for x := 0; x < s.width; x++ {
+			if s.Has(x, y) {
+				r += "X"
+			} else {
+				r += "."
+			}
+		}
+		r += "\n"
+	}
+
+	return r
+}
+
+func (s *Shape) cellIndex(x, y int) int {
+
+	if x < 0 || x >= s.width || y < 0 || y >= s.height {
+		return -1
+	}
+
+	c := y*s.width + x
+
+	// Binary search of the sorted box indices.
+	lo, hi := 0, len(s.cells)
+	for lo < hi {
+
+		m := (lo + hi) / 2
+
+		if s.cells[m] < c {
+			lo = m + 1
+		} else {
+			hi = m
+		}
+	}
+
+	if lo < len(s.cells) && s.cells[lo] == c {
+		return lo
+	}
+
+	return -1
+}
+
+// A ShapedPattern contains the content of each cell of a Shape, in the cell
+// encoding empty 0, black 1, white 2 and edge 3.
+type ShapedPattern struct {
+	shape *Shape
+	cells []uint8
+}
+
+// NewShapedPattern create an empty ShapedPattern object.
+func NewShapedPattern(s *Shape) ShapedPattern {
+
+	return ShapedPattern{
+		shape: s,
+		cells: make([]uint8, len(s.cells)),
+	}
+}
+
+// Shape returns the shape of the pattern.
+func (p *ShapedPattern) Shape() *Shape {
+
+	return p.shape
+}
+
+// SetBlack sets a black stone on a box coordinate, ignored outside the shape.
+func (p *ShapedPattern) SetBlack(x, y int) {
+
+	p.set(x, y, 1)
+}
+
+// SetWhite sets a white stone on a box coordinate, ignored outside the shape.
+func (p *ShapedPattern) SetWhite(x, y int) {
+
+	p.set(x, y, 2)
+}
+
+// SetEdge marks a box coordinate as off the board, ignored outside the shape.
+func (p *ShapedPattern) SetEdge(x, y int) {
+
+	p.set(x, y, 3)
+}
+
+func (p *ShapedPattern) set(x, y int, v uint8) {
+
+	if i := p.shape.cellIndex(x, y); i >= 0 {
+		p.cells[i] |= v
+	}
+}
+
+// Transform returns the pattern mapped by a symmetry of the shape's group,
+// with colors swapped if swap is true.
+func (p *ShapedPattern) Transform(sym Symmetry, swap bool) (ShapedPattern, error) {
+
+	for g, s := range p.shape.group {
+		if s == sym {
+			return p.transform(g, swap), nil
+		}
+	}
+
+	return ShapedPattern{}, fmt.Errorf("hash: symmetry %d does not preserve the shape", sym)
+}
+
+func (p *ShapedPattern) transform(g int, swap bool) ShapedPattern {
+
+	r := NewShapedPattern(p.shape)
+
+	for i, c := range p.cells {
+
+		if swap && (c == 1 || c == 2) {
+			c = 3 - c
+		}
+
+		r.cells[p.shape.perms[g][i]] = c
+	}
+
+	return r
+}
+
+// canonical returns the smallest transform under the shape's group, and
+// color swaps if swap is true.
+func (p *ShapedPattern) canonical(swap bool) ShapedPattern {
+
+	best := *p
+
+	for s := 0; s < 2; s++ {
+
+		if s == 1 && !swap {
+			break
+		}
+
+		for g := range p.shape.group {
+
+			if g == 0 && s == 0 {
+				continue
+			}
+
+			t := p.transform(g, s == 1)
+
+			if lessCells(t.cells, best.cells) {
+				best = t
+			}
+		}
+	}
+
+	return best
+}
+
+// GetHash64 is a canonical hash value under the shape's symmetries and color
+// reversal, computed like Pattern.GetHash64.
+func (p *ShapedPattern) GetHash64() uint64 {
+
+	c := p.canonical(true)
+
+	return c.zobrist()
+}
+
+// GetSymmetricHash64 is GetHash64 without color reversal.
+func (p *ShapedPattern) GetSymmetricHash64() uint64 {
+
+	c := p.canonical(false)
+
+	return c.zobrist()
+}
+
+// GetRelativeHash64 is GetSymmetricHash64 with colors relative to the player
+// to move, so the player to move is always black.
+func (p *ShapedPattern) GetRelativeHash64(blackToPlay bool) uint64 {
+
+	if blackToPlay {
+		return p.GetSymmetricHash64()
+	}
+
+	r := p.transform(0, true)
+
+	return r.GetSymmetricHash64()
+}
+
+func (p *ShapedPattern) zobrist() uint64 {
+
+	r := p.shape.key
+
+	for i, c := range p.cells {
+		if c != 0 {
+			r ^= ZobristKey(p.shape.key ^ (uint64(i)<<2 | uint64(c)))
+		}
+	}
+
+	return r
+}
+
+// String draws the pattern like Pattern.String with spaces outside the shape.
+func (p *ShapedPattern) String() string {
+
+	var r string
+
+	for y := 0; y < p.shape.height; y++ {
+		for x := 0; x < p.shape.width; x++ {
+
+			i := p.shape.cellIndex(x, y)
+			if i < 0 {
+				r += " "
+				continue
+			}
+
+			r += string(".XO#"[p.cells[i]])
+		}
+		r += "\n"
+	}
+
+	return r
+}
+
+func lessCells(a, b []uint8) bool {
+
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+
+	return false
+}
+
+func abs(v int) int {
+
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
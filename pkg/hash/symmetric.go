@@ -0,0 +1,69 @@
+package hash
+
+// GetSymmetricHash is a canonical hash value which only merges mirrored and
+// rotated patterns. Unlike GetHash, a pattern and its color reversed version
+// have different values.
+func (p *Pattern) GetSymmetricHash() uint64 {
+
+	t := p.newTransformer()
+	t.strict(8)
+
+	return hashWords(p.size, &t.bestBlack, &t.bestWhite)
+}
+
+// GetRelativeHash is GetSymmetricHash with colors relative to the player to
+// move, so the player to move is always black.
+func (p *Pattern) GetRelativeHash(blackToPlay bool) uint64 {
+
+	t := p.newTransformer()
+
+	if !blackToPlay {
+		t.reverse()
+	}
+
+	t.strict(8)
+
+	return hashWords(p.size, &t.bestBlack, &t.bestWhite)
+}
+
+// CanonicalSymmetric returns the pattern whose hash GetSymmetricHash computes
+// and the symmetry which maps this pattern onto it.
+func (p *Pattern) CanonicalSymmetric() (c Pattern, sym Symmetry) {
+
+	t := p.newTransformer()
+	j := t.strict(8)
+
+	c = Pattern{
+		size:  p.size,
+		black: t.bestBlack,
+		white: t.bestWhite,
+	}
+
+	return c, Symmetry(j)
+}
+
+// Reversed returns the pattern with black and white swapped.
+func (p *Pattern) Reversed() Pattern {
+
+	return Pattern{
+		size:  p.size,
+		black: p.white,
+		white: p.black,
+	}
+}
+
+// Transform returns the pattern mapped by a symmetry, with black and white
+// swapped if swapped is true. Cell (x, y) moves to sym.Apply(x, y, size).
+func (p *Pattern) Transform(sym Symmetry, swapped bool) Pattern {
+
+	r := Pattern{size: p.size}
+
+	r.black.transform(&p.black, sym, p.size)
+	r.white.transform(&p.white, sym, p.size)
+
+	if swapped {
+		r.black, r.white = r.white, r.black
+	}
+
+	return r
+}
@@ -0,0 +1,184 @@
+package hash
+
+// A 3x3 code packs the 8 neighbors of an empty point into 16 bits, 2 bits
+// each in the cell encoding empty 0, black 1, white 2 and edge 3. Neighbor k
+// is at bits 2k, clockwise from north.
+//
+//	7 0 1
+//	6 . 2
+//	5 4 3
+const NumCodes3x3 = 1 << 16
+
+// neighbors3x3 are the (x, y) offsets of each neighbor in a 3x3 code.
+var neighbors3x3 = [8][2]int{
+	{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1},
+}
+
+// A Table3x3 maps every 3x3 code to a canonical ID under the 8 symmetries,
+// colors preserved, and a weight per ID for playout policies.
+type Table3x3 struct {
+	ids     [NumCodes3x3]uint16
+	weights []float64
+}
+
+// NewTable3x3 create a Table3x3 object with all weights 1.
+func NewTable3x3() *Table3x3 {
+
+	t := &Table3x3{}
+
+	// Permutation of neighbors under each symmetry.
+	var perms [8][8]int
+
+	for s := Symmetry(0); s < 8; s++ {
+		for k, n := range neighbors3x3 {
+
+			x, y := s.Apply(n[0]+1, n[1]+1, 3)
+
+			perms[s][k] = neighborIndex3x3(x-1, y-1)
+		}
+	}
+
+	ids := map[uint16]uint16{}
+
+	for code := 0; code < NumCodes3x3; code++ {
+
+		min := uint16(code)
+
+		for s := 1; s < 8; s++ {
+			if c := permute3x3(uint16(code), &perms[s]); c < min {
+				min = c
+			}
+		}
+
+		id, ok := ids[min]
+		if !ok {
+			id = uint16(len(ids))
+			ids[min] = id
+		}
+
+		t.ids[code] = id
+	}
+
+	t.weights = make([]float64, len(ids))
+	for i := range t.weights {
+		t.weights[i] = 1
+	}
+
+	return t
+}
+
+// NumIDs returns the number of distinct canonical IDs.
+func (t *Table3x3) NumIDs() int {
+
+	return len(t.weights)
+}
+
+// ID returns the canonical ID of a code.
+func (t *Table3x3) ID(code uint16) uint16 {
+
+	return t.ids[code]
+}
+
+// Weight returns the weight of a code's canonical ID.
+func (t *Table3x3) Weight(code uint16) float64 {
+
+	return t.weights[t.ids[code]]
+}
+
+// SetWeight sets the weight of a code and all its symmetric codes.
+func (t *Table3x3) SetWeight(code uint16, w float64) {
+
+	t.weights[t.ids[code]] = w
+}
+
+// SetMatchWeight sets the weight of every code whose pattern matches a 3x3
+// MatchPattern without swapping colors. It returns the number of codes set.
+func (t *Table3x3) SetMatchWeight(m *MatchPattern, w float64) int {
+
+	if m.size != 3 {
+		return 0
+	}
+
+	r := 0
+
+	for code := 0; code < NumCodes3x3; code++ {
+
+		p := Pattern3x3(uint16(code))
+
+		if ok, _, swapped := m.Match(p); ok && !swapped {
+			t.SetWeight(uint16(code), w)
+			r++
+		}
+	}
+
+	return r
+}
+
+// Pattern3x3 returns the 3x3 pattern of a code with an empty centre.
+func Pattern3x3(code uint16) Pattern {
+
+	p := NewPattern(3)
+
+	for k, n := range neighbors3x3 {
+
+		c := code >> uint(2*k) & 3
+
+		if c&1 != 0 {
+			p.SetBlack(n[0]+1, n[1]+1)
+		}
+
+		if c&2 != 0 {
+			p.SetWhite(n[0]+1, n[1]+1)
+		}
+	}
+
+	return p
+}
+
+// Code3x3 returns the code of a 3x3 pattern, ignoring its centre.
+func Code3x3(p Pattern) uint16 {
+
+	var r uint16
+
+	for k, n := range neighbors3x3 {
+
+		c := cell(&p.black, &p.white, (n[1]+1)*3+n[0]+1)
+
+		r |= uint16(c) << uint(2*k)
+	}
+
+	return r
+}
+
+// SwapColors3x3 returns a code with black and white exchanged, so patterns
+// can be looked up relative to the player to move.
+func SwapColors3x3(code uint16) uint16 {
+
+	black := code & 0x5555 &^ (code >> 1 & 0x5555)
+	white := code & 0xaaaa &^ (code << 1 & 0xaaaa)
+
+	return code&^(black|white) | black<<1 | white>>1
+}
+
+func neighborIndex3x3(dx, dy int) int {
+
+	for k, n := range neighbors3x3 {
+		if n[0] == dx && n[1] == dy {
+			return k
+		}
+	}
+
+	return -1
+}
+
+// permute3x3 moves neighbor k of a code to neighbor perm[k].
+func permute3x3(code uint16, perm *[8]int) uint16 {
+
+	var r uint16
+
+	for k := 0; k < 8; k++ {
+		r |= (code >> uint(2*k) & 3) << uint(2*perm[k])
+	}
+
+	return r
+}
@@ -0,0 +1,48 @@
+/*
+Package countwriter provides a buffered writer for binary file formats which
+keeps the first error and the number of bytes written, so a WriteTo method
+can write without checking each call.
+*/
+package countwriter
+
+import (
+	"bufio"
+	"io"
+)
+
+// A Writer counts the bytes written and keeps the first error.
+type Writer struct {
+	w   *bufio.Writer
+	n   int64
+	err error
+}
+
+// New create a Writer object buffering writes to w.
+func New(w io.Writer) *Writer {
+
+	return &Writer{w: bufio.NewWriter(w)}
+}
+
+// Put writes b unless an earlier write failed.
+func (cw *Writer) Put(b []byte) {
+
+	if cw.err != nil {
+		return
+	}
+
+	n, err := cw.w.Write(b)
+
+	cw.n += int64(n)
+	cw.err = err
+}
+
+// Flush flushes the buffer and returns the number of bytes written and the
+// first error, as WriteTo does.
+func (cw *Writer) Flush() (int64, error) {
+
+	if cw.err == nil {
+		cw.err = cw.w.Flush()
+	}
+
+	return cw.n, cw.err
+}
@@ -0,0 +1,47 @@
+package countwriter
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+// failWriter accepts limit bytes, then fails.
+type failWriter struct {
+	limit int
+}
+
+func (f *failWriter) Write(b []byte) (int, error) {
+
+	if len(b) > f.limit {
+		n := f.limit
+		f.limit = 0
+		return n, errors.New("full")
+	}
+
+	f.limit -= len(b)
+
+	return len(b), nil
+}
+
+func TestWriter(t *testing.T) {
+
+	var buf bytes.Buffer
+
+	cw := New(&buf)
+	cw.Put([]byte("GX"))
+	cw.Put([]byte("OB"))
+
+	if n, err := cw.Flush(); n != 4 || err != nil || buf.String() != "GXOB" {
+		t.Errorf("wrote %q, %d bytes, %v", buf.String(), n, err)
+	}
+
+	// Writes fail once the buffer is flushed to a full writer.
+	cw = New(&failWriter{limit: 3})
+	cw.Put(make([]byte, 5000))
+	cw.Put([]byte("more"))
+
+	if n, err := cw.Flush(); err == nil || n != 3 {
+		t.Errorf("%d bytes, error %v, expected 3 and an error", n, err)
+	}
+}
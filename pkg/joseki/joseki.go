@@ -0,0 +1,273 @@
+/*
+Package joseki provides a library for looking up known corner sequences.
+
+A corner position is the square region of a corner with the two board edges
+beside it, taken with colors relative to the player to move. It is keyed by
+its symmetric canonical hash, so a sequence learned in one corner is found in
+all four and in both orientations along the diagonal.
+
+	Region of size 4 of the top right corner as seen on the board, edges #.
+
+	#####
+	....#
+	...X#
+	..O.#
+	....#
+*/
+package joseki
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/hash"
+	"github.com/gosharplite/goxit/pkg/sgf"
+)
+
+// Corners of the board, in the order Continuations returns them.
+const (
+	TopLeft = iota
+	TopRight
+	BottomRight
+	BottomLeft
+
+	NumCorners
+)
+
+// A Continuation is a known next move of a corner.
+type Continuation struct {
+	X, Y int
+
+	// Number of times the move was played from the position.
+	Count int
+}
+
+// A Dictionary contains continuations of corner positions.
+type Dictionary struct {
+
+	// Number of lines from the corner in a region, from 1 to hash.MaxSize-1.
+	Region int
+
+	// Continuations of canonical regions, in canonical coordinates.
+	entries map[uint64][]Continuation
+}
+
+// New create a Dictionary object with regions of a number of lines, 9 or 10
+// suit the 19x19 board.
+func New(region int) (*Dictionary, error) {
+
+	// The region and its edges make a pattern of size region+1.
+	if region < 1 || region+1 > hash.MaxSize {
+		return nil, fmt.Errorf("joseki: region %d is not between 1 and %d", region, hash.MaxSize-1)
+	}
+
+	return &Dictionary{
+		Region:  region,
+		entries: map[uint64][]Continuation{},
+	}, nil
+}
+
+// Len returns the number of corner positions with continuations.
+func (d *Dictionary) Len() int {
+
+	return len(d.entries)
+}
+
+// AddTree learns every variation of a joseki tree. Each move is a
+// continuation of the regions containing it, in the position it was played
+// from. Passes and moves away from the corners are skipped.
+func (d *Dictionary) AddTree(root *sgf.Node) error {
+
+	bd := board.NewBoard(root.Size())
+
+	return d.walk(&bd, root)
+}
+
+// walk plays the stones of a node, learns the moves of its children and
+// visits them, then restores the board.
+func (d *Dictionary) walk(bd *board.Board, n *sgf.Node) error {
+
+	played := 0
+	defer func() {
+		for ; played > 0; played-- {
+			bd.Undo()
+		}
+	}()
+
+	black, white := n.Setup()
+
+	for _, p := range black {
+
+		if err := bd.SetupBlack(bd.Point(p[0], p[1])); err != nil {
+			return fmt.Errorf("joseki: setup %v: %v", p, err)
+		}
+
+		played++
+	}
+
+	for _, p := range white {
+
+		if err := bd.SetupWhite(bd.Point(p[0], p[1])); err != nil {
+			return fmt.Errorf("joseki: setup %v: %v", p, err)
+		}
+
+		played++
+	}
+
+	if m, ok := n.Move(); ok && !m.Pass {
+
+		var err error
+
+		if m.Black {
+			err = bd.DoBlack(bd.Point(m.X, m.Y))
+		} else {
+			err = bd.DoWhite(bd.Point(m.X, m.Y))
+		}
+
+		if err != nil {
+			return fmt.Errorf("joseki: move (%d, %d): %v", m.X, m.Y, err)
+		}
+
+		played++
+	}
+
+	for _, c := range n.Children {
+
+		if m, ok := c.Move(); ok && !m.Pass {
+			d.learn(bd, m)
+		}
+
+		if err := d.walk(bd, c); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// learn adds a move as a continuation of each corner region containing it.
+func (d *Dictionary) learn(bd *board.Board, m sgf.Move) {
+
+	for c := 0; c < NumCorners; c++ {
+
+		i, j, ok := d.toRegion(bd, c, m.X, m.Y)
+		if !ok {
+			continue
+		}
+
+		p := d.region(bd, c, m.Black)
+
+		canonical, sym := p.CanonicalSymmetric()
+		key := canonical.GetSymmetricHash64()
+
+		x, y := sym.Apply(i, j, p.Size())
+
+		d.add(key, x, y)
+	}
+}
+
+func (d *Dictionary) add(key uint64, x, y int) {
+
+	cs := d.entries[key]
+
+	for i := range cs {
+		if cs[i].X == x && cs[i].Y == y {
+			cs[i].Count++
+			return
+		}
+	}
+
+	d.entries[key] = append(cs, Continuation{X: x, Y: y, Count: 1})
+}
+
+// Continuations returns the known moves of each corner for a color, in board
+// coordinates, most played first.
+func (d *Dictionary) Continuations(bd *board.Board, isBlack bool) [NumCorners][]Continuation {
+
+	var r [NumCorners][]Continuation
+
+	for c := 0; c < NumCorners; c++ {
+
+		p := d.region(bd, c, isBlack)
+
+		canonical, sym := p.CanonicalSymmetric()
+		inverse := sym.Inverse()
+
+		for _, k := range d.entries[canonical.GetSymmetricHash64()] {
+
+			i, j := inverse.Apply(k.X, k.Y, p.Size())
+			x, y := d.fromRegion(bd, c, i, j)
+
+			r[c] = append(r[c], Continuation{X: x, Y: y, Count: k.Count})
+		}
+
+		sort.SliceStable(r[c], func(a, b int) bool {
+			return r[c][a].Count > r[c][b].Count
+		})
+	}
+
+	return r
+}
+
+// region returns the region of a corner as a pattern of size Region+1 whose
+// first row and column are the board edges, with the stones of the player to
+// move black.
+func (d *Dictionary) region(bd *board.Board, c int, isBlack bool) hash.Pattern {
+
+	p := hash.NewPattern(d.Region + 1)
+
+	for j := 0; j <= d.Region; j++ {
+		for i := 0; i <= d.Region; i++ {
+
+			x, y := d.fromRegion(bd, c, i, j)
+
+			if i == 0 || j == 0 || x < 0 || y < 0 || x >= bd.Size() || y >= bd.Size() {
+				p.SetBlack(i, j)
+				p.SetWhite(i, j)
+				continue
+			}
+
+			pt := bd.Point(x, y)
+
+			switch {
+			case bd.IsEmpty(pt):
+			case bd.IsBlack(pt) == isBlack:
+				p.SetBlack(i, j)
+			default:
+				p.SetWhite(i, j)
+			}
+		}
+	}
+
+	return p
+}
+
+// fromRegion maps a region cell of a corner to a board coordinate. Cell
+// (1, 1) is the corner point.
+func (d *Dictionary) fromRegion(bd *board.Board, c, i, j int) (x, y int) {
+
+	x, y = i-1, j-1
+
+	if c == TopRight || c == BottomRight {
+		x = bd.Size() - 1 - x
+	}
+
+	if c == BottomRight || c == BottomLeft {
+		y = bd.Size() - 1 - y
+	}
+
+	return x, y
+}
+
+// toRegion maps a board coordinate to a region cell of a corner, ok is false
+// if the point is outside the region.
+func (d *Dictionary) toRegion(bd *board.Board, c, x, y int) (i, j int, ok bool) {
+
+	// The maps are their own inverse.
+	i, j = d.fromRegion(bd, c, x+1, y+1)
+	i++
+	j++
+
+	return i, j, i >= 1 && j >= 1 && i <= d.Region && j <= d.Region
+}
@@ -0,0 +1,155 @@
+package joseki
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/sgf"
+)
+
+// A tree in the top right corner: the 4-4 point with two approaches, and the
+// 3-4 point.
+const tree = `(;SZ[19]
+	(;B[pd](;W[qf];B[nc];W[rd])(;W[nc];B[pf]))
+	(;B[qd];W[oc]))`
+
+func dictionary(t *testing.T) *Dictionary {
+
+	roots, err := sgf.Parse(tree)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	d, err := New(9)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := d.AddTree(roots[0]); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	return d
+}
+
+func points(cs []Continuation) [][2]int {
+
+	var r [][2]int
+
+	for _, c := range cs {
+		r = append(r, [2]int{c.X, c.Y})
+	}
+
+	return r
+}
+
+func TestContinuations(t *testing.T) {
+
+	d := dictionary(t)
+
+	bd := board.NewBoard(19)
+
+	// Every empty corner starts with the 4-4 and the 3-4 point. The 3-4
+	// point and its mirror along the diagonal are the same position.
+	empty := d.Continuations(&bd, true)
+
+	expected := [NumCorners][][2]int{
+		TopLeft:     {{3, 3}, {2, 3}},
+		TopRight:    {{15, 3}, {16, 3}},
+		BottomRight: {{15, 15}, {16, 15}},
+		BottomLeft:  {{3, 15}, {2, 15}},
+	}
+
+	for c := 0; c < NumCorners; c++ {
+		if !reflect.DeepEqual(points(empty[c]), expected[c]) {
+			t.Errorf("corner %d: %v, expected %v", c, points(empty[c]), expected[c])
+		}
+	}
+
+	cases := map[string]struct {
+		black, white [][2]int
+		isBlack      bool
+		corner       int
+		expected     [][2]int
+	}{
+		"bottom left approach": {
+			black:    [][2]int{{3, 15}},
+			corner:   BottomLeft,
+			expected: [][2]int{{2, 13}, {5, 16}},
+		},
+		"colors reversed": {
+			white:    [][2]int{{3, 3}},
+			isBlack:  true,
+			corner:   TopLeft,
+			expected: [][2]int{{2, 5}, {5, 2}},
+		},
+		"learned corner": {
+			black:    [][2]int{{15, 3}, {13, 2}},
+			white:    [][2]int{{16, 5}},
+			isBlack:  false,
+			corner:   TopRight,
+			expected: [][2]int{{17, 3}},
+		},
+		"transposed": {
+			black:    [][2]int{{3, 3}, {2, 5}},
+			white:    [][2]int{{5, 2}},
+			isBlack:  false,
+			corner:   TopLeft,
+			expected: [][2]int{{3, 1}},
+		},
+		"unknown": {
+			black:  [][2]int{{9, 9}, {2, 2}},
+			corner: TopLeft,
+		},
+	}
+
+	for k, tc := range cases {
+
+		bd := board.NewBoard(19)
+
+		for _, p := range tc.black {
+			bd.DoBlack(bd.Point(p[0], p[1]))
+		}
+
+		for _, p := range tc.white {
+			bd.DoWhite(bd.Point(p[0], p[1]))
+		}
+
+		r := d.Continuations(&bd, tc.isBlack)
+
+		if !reflect.DeepEqual(points(r[tc.corner]), tc.expected) {
+			t.Errorf("%s: %v, expected %v", k, points(r[tc.corner]), tc.expected)
+		}
+	}
+}
+
+func TestAddTreeError(t *testing.T) {
+
+	roots, err := sgf.Parse("(;SZ[9];B[cc];W[cc])")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	d, err := New(5)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := d.AddTree(roots[0]); err == nil {
+		t.Error("illegal move learned without error")
+	}
+}
+
+func TestNewError(t *testing.T) {
+
+	for _, region := range []int{-1, 0, 25} {
+		if _, err := New(region); err == nil {
+			t.Errorf("region %d: created without error", region)
+		}
+	}
+
+	if _, err := New(24); err != nil {
+		t.Errorf("region 24: %v", err)
+	}
+}
@@ -0,0 +1,245 @@
+/*
+Package matcher provides a library for finding a set of Go patterns on a board.
+
+Patterns are compiled into an index of their 16 mirrored, rotated and color
+reversed forms, so each window of the board is looked up once per pattern
+size. Hits are kept per point and follow the moves and undos of the board,
+rechecking only the windows around the points the board reports changed.
+*/
+package matcher
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/hash"
+)
+
+// A Hit is a pattern found on the board. Cell (x, y) of the pattern, with
+// colors swapped if Swapped is true, is at cell Sym.Apply(x, y, size) of the
+// window centred on Point, as in hash.MatchPattern.Match.
+type Hit struct {
+	Point   int
+	Pattern int
+	Sym     hash.Symmetry
+	Swapped bool
+}
+
+// variant is one distinct transform of a pattern.
+type variant struct {
+	pattern int
+	sym     hash.Symmetry
+	swapped bool
+}
+
+// A Matcher finds the patterns of a set on a board.
+type Matcher struct {
+
+	// Distinct pattern sizes, increasing.
+	sizes []int
+
+	// Transformed patterns to the patterns they come from.
+	index map[hash.Pattern][]variant
+
+	// Board the hits belong to, and its change count when they were last
+	// brought up to date.
+	bd   *board.Board
+	seen uint64
+	hits [][]Hit
+
+	// Buffers of changed points and windows to recheck.
+	changed []int
+	dirty   []bool
+}
+
+// New create a Matcher object for patterns of odd sizes, at most
+// hash.MaxSize as for any Pattern, their edges given by cells set both black
+// and white. Hits name patterns by index.
+func New(patterns []hash.Pattern) (*Matcher, error) {
+
+	m := &Matcher{
+		index: map[hash.Pattern][]variant{},
+	}
+
+	for i := range patterns {
+
+		p := &patterns[i]
+
+		if p.Size()%2 == 0 {
+			return nil, fmt.Errorf("matcher: pattern %d has even size %d", i, p.Size())
+		}
+
+		m.addSize(p.Size())
+
+		seen := map[hash.Pattern]bool{}
+
+		for j := 0; j < 16; j++ {
+
+			v := variant{
+				pattern: i,
+				sym:     hash.Symmetry(j % 8),
+				swapped: j >= 8,
+			}
+
+			t := p.Transform(v.sym, v.swapped)
+
+			// Symmetric patterns are reported once per window.
+			if seen[t] {
+				continue
+			}
+			seen[t] = true
+
+			m.index[t] = append(m.index[t], v)
+		}
+	}
+
+	if len(m.sizes) == 0 {
+		return nil, errors.New("matcher: no patterns")
+	}
+
+	return m, nil
+}
+
+func (m *Matcher) addSize(size int) {
+
+	for _, s := range m.sizes {
+		if s == size {
+			return
+		}
+	}
+
+	m.sizes = append(m.sizes, size)
+
+	sort.Ints(m.sizes)
+}
+
+// Update attaches the matcher to a board and brings the hits up to date.
+// Attaching scans every point; afterwards At and Hits follow the moves and
+// undos of the board on their own, rechecking the windows around changed
+// points.
+func (m *Matcher) Update(bd *board.Board) {
+
+	if m.bd != bd {
+		m.bd = bd
+		m.reset()
+		return
+	}
+
+	m.sync()
+}
+
+// sync rechecks the windows around the points changed since the last check.
+func (m *Matcher) sync() {
+
+	if m.bd == nil || m.bd.ChangeCount() == m.seen {
+		return
+	}
+
+	changed, ok := m.bd.ChangedSince(m.changed[:0], m.seen)
+	m.changed = changed
+
+	if !ok {
+		m.reset()
+		return
+	}
+
+	m.seen = m.bd.ChangeCount()
+
+	size := m.bd.Size()
+	radius := m.sizes[len(m.sizes)-1] / 2
+
+	var windows []int
+
+	for _, pt := range changed {
+
+		x, y := m.bd.XY(pt)
+
+		for dy := -radius; dy <= radius; dy++ {
+			for dx := -radius; dx <= radius; dx++ {
+
+				if x+dx < 0 || y+dy < 0 || x+dx >= size || y+dy >= size {
+					continue
+				}
+
+				w := m.bd.Point(x+dx, y+dy)
+
+				if !m.dirty[w] {
+					m.dirty[w] = true
+					windows = append(windows, w)
+				}
+			}
+		}
+	}
+
+	for _, w := range windows {
+		m.dirty[w] = false
+		m.check(w)
+	}
+}
+
+// reset forgets all hits and checks every point.
+func (m *Matcher) reset() {
+
+	size := m.bd.Size()
+
+	l := (size+2)*(size+1) + 1
+
+	m.seen = m.bd.ChangeCount()
+	m.hits = make([][]Hit, l)
+	m.dirty = make([]bool, l)
+
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			m.check(m.bd.Point(x, y))
+		}
+	}
+}
+
+// check finds the hits of the windows centred on a point.
+func (m *Matcher) check(pt int) {
+
+	m.hits[pt] = m.hits[pt][:0]
+
+	for _, s := range m.sizes {
+
+		w := m.bd.Pattern(pt, s)
+
+		for _, v := range m.index[w] {
+			m.hits[pt] = append(m.hits[pt], Hit{
+				Point:   pt,
+				Pattern: v.pattern,
+				Sym:     v.sym,
+				Swapped: v.swapped,
+			})
+		}
+	}
+}
+
+// At returns the hits of the windows centred on a point. The slice is reused
+// once the board changes.
+func (m *Matcher) At(pt int) []Hit {
+
+	m.sync()
+
+	if pt < 0 || pt >= len(m.hits) {
+		return nil
+	}
+
+	return m.hits[pt]
+}
+
+// Hits returns every hit, ordered by point.
+func (m *Matcher) Hits() []Hit {
+
+	m.sync()
+
+	var r []Hit
+
+	for _, h := range m.hits {
+		r = append(r, h...)
+	}
+
+	return r
+}
@@ -0,0 +1,186 @@
+package matcher
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/hash"
+)
+
+var library = []string{
+	`
+	...
+	.X.
+	...
+	`,
+	`
+	.O.
+	XX.
+	...
+	`,
+	`
+	###
+	.X.
+	...
+	`,
+	`
+	#####
+	#####
+	##...
+	##.X.
+	##...
+	`,
+}
+
+func compile(t *testing.T) []hash.Pattern {
+
+	var r []hash.Pattern
+
+	for _, s := range library {
+
+		p, err := hash.ParsePattern(s)
+		if err != nil {
+			t.Fatal(err.Error())
+		}
+
+		r = append(r, p)
+	}
+
+	return r
+}
+
+func TestMatcher(t *testing.T) {
+
+	patterns := compile(t)
+
+	m, err := New(patterns)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	bd := board.NewBoard(9)
+
+	bd.DoBlack(bd.Point(4, 4))
+	bd.DoWhite(bd.Point(4, 3))
+	bd.DoBlack(bd.Point(3, 4))
+
+	bd.DoWhite(bd.Point(7, 7))
+	bd.DoBlack(bd.Point(7, 6))
+	bd.DoWhite(bd.Point(6, 7))
+
+	bd.DoBlack(bd.Point(1, 1))
+	bd.DoBlack(bd.Point(6, 0))
+
+	m.Update(&bd)
+
+	cases := map[string]struct {
+		x, y    int
+		pattern int
+		found   bool
+	}{
+		"hane":         {4, 4, 1, true},
+		"swapped hane": {7, 7, 1, true},
+		"not hane":     {3, 4, 1, false},
+		"lone stone":   {1, 1, 0, true},
+		"not lone":     {4, 4, 0, false},
+		"corner":       {0, 0, 3, true},
+		"not corner":   {8, 8, 3, false},
+		"edge":         {6, 0, 2, true},
+		"not edge":     {1, 1, 2, false},
+	}
+
+	for k, tc := range cases {
+
+		pt := bd.Point(tc.x, tc.y)
+
+		var hit *Hit
+		for _, h := range m.At(pt) {
+			if h.Pattern == tc.pattern {
+				h := h
+				hit = &h
+			}
+		}
+
+		if (hit != nil) != tc.found {
+			t.Errorf("%s: found %v, expected %v", k, hit != nil, tc.found)
+			continue
+		}
+
+		if hit == nil {
+			continue
+		}
+
+		w := bd.Pattern(pt, patterns[hit.Pattern].Size())
+
+		if patterns[hit.Pattern].Transform(hit.Sym, hit.Swapped) != w {
+			t.Errorf("%s: symmetry %d swapped %v does not map the pattern onto\n%s", k, hit.Sym, hit.Swapped, w.String())
+		}
+	}
+
+	if _, err := New([]hash.Pattern{hash.NewPattern(2)}); err == nil {
+		t.Error("even pattern compiled without error")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+
+	patterns := compile(t)
+
+	m, _ := New(patterns)
+
+	rnd := rand.New(rand.NewSource(1))
+
+	bd := board.NewBoard(9)
+
+	// Hits follow the board once attached.
+	m.Update(&bd)
+
+	for n := 0; n < 300; n++ {
+
+		if bd.Depth() > 0 && rnd.Intn(4) == 0 {
+			bd.Undo()
+		} else {
+
+			pt := bd.Point(rnd.Intn(9), rnd.Intn(9))
+
+			if bd.BlackToPlay() {
+				bd.DoBlack(pt)
+			} else {
+				bd.DoWhite(pt)
+			}
+		}
+
+		fresh, _ := New(patterns)
+		fresh.Update(&bd)
+
+		if !reflect.DeepEqual(m.Hits(), fresh.Hits()) {
+			t.Fatalf("move %d: incremental hits differ from a full scan of\n%s", n, bd.String())
+		}
+	}
+
+	// Playouts and undos make more changes than the board keeps.
+	before := bd.ChangeCount()
+
+	for {
+
+		moves := bd.Playout(rnd)
+
+		if _, ok := bd.ChangedSince(nil, before); !ok {
+
+			fresh, _ := New(patterns)
+			fresh.Update(&bd)
+
+			if !reflect.DeepEqual(m.Hits(), fresh.Hits()) {
+				t.Fatalf("hits differ from a full scan after a playout of\n%s", bd.String())
+			}
+
+			break
+		}
+
+		for ; moves > 0; moves-- {
+			bd.Undo()
+		}
+	}
+}
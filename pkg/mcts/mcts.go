@@ -0,0 +1,187 @@
+/*
+Package mcts provides a Monte Carlo tree search over board.Board.
+
+The tree is not stored as nodes but in a transposition table keyed by the
+position, so different move orders reaching the same position share their
+statistics. Moves are chosen by UCB1 on the children's statistics and new
+positions are evaluated by a random playout scored by area.
+*/
+package mcts
+
+import (
+	"math"
+	"math/rand"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/tt"
+)
+
+// A Search finds moves by Monte Carlo tree search. Searches on separate
+// boards may share a table and run concurrently.
+type Search struct {
+	Table *tt.Table
+
+	// Points given to white.
+	Komi float64
+
+	// Weight of the exploration term of UCB1.
+	Exploration float64
+}
+
+// New create a Search object using a table.
+func New(table *tt.Table) *Search {
+
+	return &Search{
+		Table:       table,
+		Komi:        7.5,
+		Exploration: 0.7,
+	}
+}
+
+// Run runs a number of simulations from the position, the player to move
+// being board.BlackToPlay, and returns the most visited move, 0 to pass. The
+// board is restored before returning.
+func (s *Search) Run(bd *board.Board, simulations int, rnd *rand.Rand) int {
+
+	isBlack := bd.BlackToPlay()
+
+	for i := 0; i < simulations; i++ {
+		s.simulate(bd, isBlack, rnd)
+	}
+
+	best := 0
+	visits := uint32(0)
+
+	for _, pt := range candidates(bd, isBlack) {
+
+		if bd.Do(pt, isBlack) != nil {
+			continue
+		}
+
+		e, _ := s.Table.Probe(bd.Key(!isBlack))
+		bd.Undo()
+
+		if e.Visits > visits {
+			best = pt
+			visits = e.Visits
+		}
+	}
+
+	s.Table.SetBest(bd.Key(isBlack), best)
+
+	return best
+}
+
+// simulate descends the tree to a new position, plays it out and records
+// the result along the path.
+func (s *Search) simulate(bd *board.Board, isBlack bool, rnd *rand.Rand) {
+
+	var path []uint64
+
+	rootBlack := isBlack
+	moves := 0
+	limit := 2 * bd.Size() * bd.Size()
+
+	for {
+
+		key := bd.Key(isBlack)
+		path = append(path, key)
+
+		e, ok := s.Table.Probe(key)
+		if !ok || len(path) > limit {
+			break
+		}
+
+		pt := s.selectMove(bd, isBlack, e.Visits, rnd)
+		if pt == 0 || bd.Do(pt, isBlack) != nil {
+			break
+		}
+
+		moves++
+		isBlack = !isBlack
+	}
+
+	moves += bd.Playout(rnd)
+
+	result := 0.0
+	if float64(bd.AreaScore()) > s.Komi {
+		result = 1
+	}
+
+	for ; moves > 0; moves-- {
+		bd.Undo()
+	}
+
+	// Entries hold the result of the player to move.
+	for i, key := range path {
+
+		if (i%2 == 0) == rootBlack {
+			s.Table.Add(key, result)
+		} else {
+			s.Table.Add(key, 1-result)
+		}
+	}
+}
+
+// selectMove returns the move maximizing UCB1 for the player to move, 0 if
+// there is none. Unvisited moves come first, in random order.
+func (s *Search) selectMove(bd *board.Board, isBlack bool, visits uint32, rnd *rand.Rand) int {
+
+	cs := candidates(bd, isBlack)
+
+	rnd.Shuffle(len(cs), func(i, j int) {
+		cs[i], cs[j] = cs[j], cs[i]
+	})
+
+	best := 0
+	bestValue := math.Inf(-1)
+
+	logN := math.Log(float64(visits))
+
+	for _, pt := range cs {
+
+		if bd.Do(pt, isBlack) != nil {
+			continue
+		}
+
+		e, ok := s.Table.Probe(bd.Key(!isBlack))
+		bd.Undo()
+
+		if !ok || e.Visits == 0 {
+			return pt
+		}
+
+		// The child holds the result of the opponent.
+		v := 1 - e.Mean() + s.Exploration*math.Sqrt(logN/float64(e.Visits))
+
+		if v > bestValue {
+			best = pt
+			bestValue = v
+		}
+	}
+
+	return best
+}
+
+// candidates returns the legal moves of a color which do not fill its eyes.
+func candidates(bd *board.Board, isBlack bool) []int {
+
+	var r []int
+
+	for y := 0; y < bd.Size(); y++ {
+		for x := 0; x < bd.Size(); x++ {
+
+			pt := bd.Point(x, y)
+
+			if !bd.IsEmpty(pt) || bd.IsEye(pt, isBlack) {
+				continue
+			}
+
+			if isBlack && bd.IsLegalBlack(pt) == nil || !isBlack && bd.IsLegalWhite(pt) == nil {
+				r = append(r, pt)
+			}
+		}
+	}
+
+	return r
+}
@@ -0,0 +1,92 @@
+package mcts
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/tt"
+)
+
+// capture sets up a 5x5 position where black wins by capturing the white
+// group at (3, 0).
+//
+//	. X O . .
+//	X O O X .
+//	. X O X .
+//	. . X . .
+//	. . . . .
+func capture() *board.Board {
+
+	bd := board.NewBoard(5)
+
+	black := [][2]int{{1, 0}, {0, 1}, {3, 1}, {1, 2}, {3, 2}, {2, 3}}
+	white := [][2]int{{2, 0}, {1, 1}, {2, 1}, {2, 2}}
+
+	for i, p := range black {
+
+		bd.DoBlack(bd.Point(p[0], p[1]))
+
+		if i < len(white) {
+			bd.DoWhite(bd.Point(white[i][0], white[i][1]))
+		}
+	}
+
+	// White moved last.
+	bd.DoWhite(bd.Point(4, 4))
+
+	return &bd
+}
+
+func TestRun(t *testing.T) {
+
+	bd := capture()
+	before := bd.String()
+
+	s := New(tt.New(1 << 16))
+	s.Komi = 0.5
+
+	pt := s.Run(bd, 1000, rand.New(rand.NewSource(1)))
+
+	if x, y := bd.XY(pt); x != 3 || y != 0 {
+		t.Errorf("move (%d, %d), expected (3, 0)", x, y)
+	}
+
+	if bd.String() != before {
+		t.Errorf("board not restored\n%s", bd.String())
+	}
+
+	e, ok := s.Table.Probe(bd.Key(true))
+	if !ok || e.Visits != 1000 || int(e.Best) != pt {
+		t.Errorf("root %+v, expected 1000 visits and best %d", e, pt)
+	}
+}
+
+func TestShared(t *testing.T) {
+
+	table := tt.New(1 << 16)
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < 4; g++ {
+
+		wg.Add(1)
+
+		go func(seed int64) {
+
+			defer wg.Done()
+
+			s := New(table)
+			s.Run(capture(), 200, rand.New(rand.NewSource(seed)))
+		}(int64(g))
+	}
+
+	wg.Wait()
+
+	bd := capture()
+
+	if e, _ := table.Probe(bd.Key(true)); e.Visits != 800 {
+		t.Errorf("root visited %d times, expected 800", e.Visits)
+	}
+}
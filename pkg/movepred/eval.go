@@ -0,0 +1,151 @@
+package movepred
+
+import (
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/sgf"
+)
+
+// A Predictor ranks the legal moves of a position, best first. The board
+// must be restored before returning.
+type Predictor interface {
+	Predict(bd *board.Board, isBlack bool) []int
+}
+
+// Predict returns the points of Rank.
+func (m *Model) Predict(bd *board.Board, isBlack bool) []int {
+
+	cs := m.Rank(bd, isBlack)
+
+	r := make([]int, len(cs))
+	for i, c := range cs {
+		r[i] = c.Point
+	}
+
+	return r
+}
+
+// A Stats accumulates how well a predictor found expert moves.
+type Stats struct {
+
+	// Hits[k] is the number of expert moves ranked k+1 or better.
+	Hits []int
+
+	// Positions is the number of expert moves seen.
+	Positions int
+
+	// RankSum is the sum of the expert moves' ranks, starting at 1. A move
+	// missing from the prediction ranks after every predicted move.
+	RankSum int
+}
+
+// NewStats create a Stats object tracking ranks up to k.
+func NewStats(k int) Stats {
+
+	return Stats{Hits: make([]int, k)}
+}
+
+// Add records the rank of an expert move, starting at 1.
+func (s *Stats) Add(rank int) {
+
+	s.Positions++
+	s.RankSum += rank
+
+	for k := rank - 1; k >= 0 && k < len(s.Hits); k++ {
+		s.Hits[k]++
+	}
+}
+
+// Accuracy returns the fraction of expert moves ranked k or better.
+func (s *Stats) Accuracy(k int) float64 {
+
+	if s.Positions == 0 || k < 1 || k > len(s.Hits) {
+		return 0
+	}
+
+	return float64(s.Hits[k-1]) / float64(s.Positions)
+}
+
+// MeanRank returns the average rank of expert moves.
+func (s *Stats) MeanRank() float64 {
+
+	if s.Positions == 0 {
+		return 0
+	}
+
+	return float64(s.RankSum) / float64(s.Positions)
+}
+
+// An Evaluation splits Stats by game phase.
+type Evaluation struct {
+
+	// Phases holds the first move number of each phase after the first,
+	// e.g. {31, 151} gives moves 1-30, 31-150 and 151 onwards.
+	Phases []int
+
+	Total   Stats
+	ByPhase []Stats
+
+	predictor Predictor
+}
+
+// NewEvaluation create an Evaluation object.
+func NewEvaluation(p Predictor, k int, phases []int) Evaluation {
+
+	e := Evaluation{
+		Phases:    phases,
+		Total:     NewStats(k),
+		ByPhase:   make([]Stats, len(phases)+1),
+		predictor: p,
+	}
+
+	for i := range e.ByPhase {
+		e.ByPhase[i] = NewStats(k)
+	}
+
+	return e
+}
+
+// Phase returns the phase of a move number, starting at 1.
+func (e *Evaluation) Phase(move int) int {
+
+	r := 0
+
+	for r < len(e.Phases) && move >= e.Phases[r] {
+		r++
+	}
+
+	return r
+}
+
+// AddGame asks the predictor for every non-pass move of a game's main line.
+func (e *Evaluation) AddGame(root *sgf.Node) error {
+
+	move := 0
+
+	return sgf.Replay(root, func(bd *board.Board, m sgf.Move) error {
+
+		move++
+
+		if m.Pass {
+			return nil
+		}
+
+		expert := bd.Point(m.X, m.Y)
+
+		predicted := e.predictor.Predict(bd, m.Black)
+
+		rank := len(predicted) + 1
+
+		for i, pt := range predicted {
+			if pt == expert {
+				rank = i + 1
+				break
+			}
+		}
+
+		e.Total.Add(rank)
+		e.ByPhase[e.Phase(move)].Add(rank)
+
+		return nil
+	})
+}
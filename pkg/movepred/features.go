@@ -0,0 +1,191 @@
+/*
+Package movepred provides a library for predicting expert moves in the game of Go.
+
+It follows 'Move Prediction in the Game of Go' by Brett Alexander Harrison and
+'Computing Elo Ratings of Move Patterns in the Game of Go' by Remi Coulom.
+http://www.eecs.harvard.edu/econcs/pubs/Harrisonthesis.pdf
+
+Each legal move is described by a set of features. Each feature has a strength
+gamma, a move's strength is the product of its features' gammas and the
+probability of a move being played is its strength over the sum of all legal
+moves' strengths. Gammas are trained by minorization-maximization on games.
+*/
+package movepred
+
+import (
+	"github.com/gosharplite/goxit/pkg/board"
+)
+
+// A Feature is a value within a feature group. Values use all 64 bits, so
+// pattern hashes are kept whole.
+type Feature struct {
+	group uint8
+	value uint64
+}
+
+// Feature groups. A move has at most one feature of each group.
+const (
+	Pattern uint8 = iota
+	DistLast
+	DistPrev
+	Atari
+	Capture
+	Edge
+
+	numGroups
+)
+
+// maxDistance is the largest distance feature, further moves share it.
+const maxDistance = 17
+
+// NewFeature combines a group and a value.
+func NewFeature(group uint8, value uint64) Feature {
+
+	return Feature{group: group, value: value}
+}
+
+// Group returns the feature group.
+func (f Feature) Group() uint8 {
+
+	return f.group
+}
+
+// Value returns the feature value.
+func (f Feature) Value() uint64 {
+
+	return f.value
+}
+
+// less orders features by group, then value.
+func (f Feature) less(g Feature) bool {
+
+	if f.group != g.group {
+		return f.group < g.group
+	}
+
+	return f.value < g.value
+}
+
+// An Extractor computes the features of candidate moves.
+type Extractor struct {
+
+	// Side of the square pattern centred on the move, odd and at most
+	// hash.MaxSize.
+	PatternSize int
+}
+
+// NewExtractor create an Extractor object.
+func NewExtractor() Extractor {
+
+	return Extractor{PatternSize: 3}
+}
+
+// Extract returns the features of playing pt. It returns nil if the move is
+// illegal. The board is restored before returning.
+func (e *Extractor) Extract(bd *board.Board, pt int, isBlack bool) []Feature {
+
+	if !bd.IsEmpty(pt) {
+		return nil
+	}
+
+	p := bd.Pattern(pt, e.PatternSize)
+
+	// Patterns are seen from the mover, whose stones are black.
+	r := []Feature{NewFeature(Pattern, p.GetRelativeHash64(isBlack))}
+
+	// Passes and setup stones have no distance.
+	if d := bd.Depth(); d >= 1 && !bd.IsSetup(d) {
+
+		if last, _ := bd.Move(d); last != 0 {
+			r = append(r, NewFeature(DistLast, distance(bd, pt, last)))
+		}
+
+		if d >= 2 && !bd.IsSetup(d-1) {
+			if prev, _ := bd.Move(d - 1); prev != 0 {
+				r = append(r, NewFeature(DistPrev, distance(bd, pt, prev)))
+			}
+		}
+	}
+
+	x, y := bd.XY(pt)
+	edge := min(min(x, y), min(bd.Size()-1-x, bd.Size()-1-y))
+	if edge < 4 {
+		r = append(r, NewFeature(Edge, uint64(edge)))
+	}
+
+	bb, wb := bd.Prisoners()
+
+	var err error
+
+	if isBlack {
+		err = bd.DoBlack(pt)
+	} else {
+		err = bd.DoWhite(pt)
+	}
+
+	if err != nil {
+		return nil
+	}
+
+	ba, wa := bd.Prisoners()
+
+	if n := ba - bb + wa - wb; n > 0 {
+		r = append(r, NewFeature(Capture, uint64(min(n, 3))))
+	}
+
+	for _, n := range bd.Neighbors(pt) {
+
+		opponent := bd.IsWhite(n)
+		if !isBlack {
+			opponent = bd.IsBlack(n)
+		}
+
+		if opponent && bd.NumLiberties(n) == 1 {
+			r = append(r, NewFeature(Atari, 1))
+			break
+		}
+	}
+
+	bd.Undo()
+
+	return r
+}
+
+// distance is dx+dy+max(dx,dy) capped at maxDistance.
+func distance(bd *board.Board, a, b int) uint64 {
+
+	ax, ay := bd.XY(a)
+	bx, by := bd.XY(b)
+
+	dx := abs(ax - bx)
+	dy := abs(ay - by)
+
+	return uint64(min(dx+dy+max(dx, dy), maxDistance))
+}
+
+func abs(v int) int {
+
+	if v < 0 {
+		return -v
+	}
+
+	return v
+}
+
+func min(a, b int) int {
+
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func max(a, b int) int {
+
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,159 @@
+package movepred
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/hash"
+)
+
+// A Model contains the trained strength of each feature. Unknown features
+// have strength 1.
+type Model struct {
+	Extractor Extractor
+
+	Gammas map[Feature]float64
+}
+
+// A Candidate is a legal move with its predicted probability.
+type Candidate struct {
+	Point       int
+	Gamma       float64
+	Probability float64
+}
+
+// NewModel create a Model object with no trained features.
+func NewModel(e Extractor) Model {
+
+	return Model{
+		Extractor: e,
+		Gammas:    map[Feature]float64{},
+	}
+}
+
+// Strength returns the product of the gammas of a move's features.
+func (m *Model) Strength(fs []Feature) float64 {
+
+	r := 1.0
+
+	for _, f := range fs {
+		if g, ok := m.Gammas[f]; ok {
+			r *= g
+		}
+	}
+
+	return r
+}
+
+// Rank returns the legal moves of a color ordered from most to least likely.
+// The board is restored before returning.
+func (m *Model) Rank(bd *board.Board, isBlack bool) []Candidate {
+
+	var r []Candidate
+
+	total := 0.0
+
+	for y := 0; y < bd.Size(); y++ {
+		for x := 0; x < bd.Size(); x++ {
+
+			pt := bd.Point(x, y)
+
+			if !bd.IsEmpty(pt) {
+				continue
+			}
+
+			fs := m.Extractor.Extract(bd, pt, isBlack)
+			if fs == nil {
+				continue
+			}
+
+			g := m.Strength(fs)
+
+			r = append(r, Candidate{Point: pt, Gamma: g})
+
+			total += g
+		}
+	}
+
+	for i := range r {
+		r[i].Probability = r[i].Gamma / total
+	}
+
+	sort.SliceStable(r, func(i, j int) bool {
+		return r[i].Gamma > r[j].Gamma
+	})
+
+	return r
+}
+
+// WriteTo writes the model as text, one "group value gamma" line per feature
+// preceded by the pattern size. Values are in hexadecimal.
+func (m *Model) WriteTo(w io.Writer) (int64, error) {
+
+	bw := bufio.NewWriter(w)
+
+	var total int64
+
+	n, err := fmt.Fprintf(bw, "patternsize %d\n", m.Extractor.PatternSize)
+	total += int64(n)
+	if err != nil {
+		return total, err
+	}
+
+	fs := make([]Feature, 0, len(m.Gammas))
+	for f := range m.Gammas {
+		fs = append(fs, f)
+	}
+
+	sort.Slice(fs, func(i, j int) bool {
+		return fs[i].less(fs[j])
+	})
+
+	for _, f := range fs {
+
+		n, err := fmt.Fprintf(bw, "%d %016x %g\n", f.group, f.value, m.Gammas[f])
+		total += int64(n)
+		if err != nil {
+			return total, err
+		}
+	}
+
+	return total, bw.Flush()
+}
+
+// ReadModel reads a model written by WriteTo.
+func ReadModel(r io.Reader) (Model, error) {
+
+	m := NewModel(NewExtractor())
+
+	br := bufio.NewReader(r)
+
+	if _, err := fmt.Fscanf(br, "patternsize %d\n", &m.Extractor.PatternSize); err != nil {
+		return m, fmt.Errorf("movepred: reading header: %v", err)
+	}
+
+	if s := m.Extractor.PatternSize; s < 1 || s%2 == 0 || s > hash.MaxSize {
+		return m, fmt.Errorf("movepred: pattern size %d is not odd and between 1 and %d", s, hash.MaxSize)
+	}
+
+	for {
+		var f Feature
+		var g float64
+
+		_, err := fmt.Fscanf(br, "%d %x %g\n", &f.group, &f.value, &g)
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			return m, fmt.Errorf("movepred: reading gamma: %v", err)
+		}
+
+		m.Gammas[f] = g
+	}
+
+	return m, nil
+}
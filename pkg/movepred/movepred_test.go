@@ -0,0 +1,200 @@
+package movepred
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/sgf"
+)
+
+// Both players always answer directly below the previous move.
+const game = "(;SZ[9];B[ab];W[ac];B[bb];W[bc];B[cb];W[cc];B[db];W[dc];B[eb];W[ec];B[fb];W[fc])"
+
+func TestFeature(t *testing.T) {
+
+	f := NewFeature(DistLast, 42)
+
+	if f.Group() != DistLast || f.Value() != 42 {
+		t.Errorf("group %d value %d, expected %d and 42", f.Group(), f.Value(), DistLast)
+	}
+
+	// Pattern hashes use the top bits.
+	p := NewFeature(Pattern, 0xff00000000000001)
+
+	if p.Value() != 0xff00000000000001 || p == NewFeature(Pattern, 1) {
+		t.Errorf("value %#x, expected 0xff00000000000001", p.Value())
+	}
+}
+
+func TestExtract(t *testing.T) {
+
+	bh := board.NewBoard(9)
+
+	bh.DoBlack(bh.Point(0, 1))
+	bh.DoWhite(bh.Point(1, 1))
+	bh.DoBlack(bh.Point(4, 4))
+	bh.DoWhite(bh.Point(0, 2))
+
+	e := NewExtractor()
+
+	// Black atari on the white stone at (0, 2).
+	fs := e.Extract(&bh, bh.Point(0, 3), true)
+
+	expected := map[Feature]bool{
+		NewFeature(DistLast, 2): true,
+		NewFeature(DistPrev, 9): true,
+		NewFeature(Edge, 0):     true,
+		NewFeature(Atari, 1):    true,
+	}
+
+	for _, f := range fs {
+		delete(expected, f)
+	}
+
+	for f := range expected {
+		t.Errorf("missing group %d value %d", f.Group(), f.Value())
+	}
+
+	if e.Extract(&bh, bh.Point(0, 1), true) != nil {
+		t.Error("occupied point has features")
+	}
+}
+
+func TestTrain(t *testing.T) {
+
+	roots, err := sgf.Parse(game)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	tr := NewTrainer(NewExtractor())
+
+	if err := tr.AddGame(roots[0]); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if tr.NumPositions() != 12 {
+		t.Errorf("%d positions, expected 12", tr.NumPositions())
+	}
+
+	m := tr.Train(10)
+
+	near := m.Gammas[NewFeature(DistLast, 2)]
+	far := m.Gammas[NewFeature(DistLast, maxDistance)]
+
+	if near <= far {
+		t.Errorf("gamma of distance 2 is %v, not more than far %v", near, far)
+	}
+
+	bh := board.NewBoard(9)
+
+	bh.DoBlack(bh.Point(6, 5))
+
+	r := m.Rank(&bh, false)
+
+	if len(r) != 80 {
+		t.Errorf("%d candidates, expected 80", len(r))
+	}
+
+	if x, y := bh.XY(r[0].Point); abs(x-6)+abs(y-5) != 1 {
+		t.Errorf("best move (%d, %d) is not next to the last move", x, y)
+	}
+
+	var buf bytes.Buffer
+
+	if _, err := m.WriteTo(&buf); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	read, err := ReadModel(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if read.Extractor.PatternSize != m.Extractor.PatternSize || len(read.Gammas) != len(m.Gammas) {
+		t.Errorf("model changed after reading back")
+	}
+
+	for f, g := range m.Gammas {
+		if read.Gammas[f] != g {
+			t.Errorf("feature %d %x gamma %v, expected %v", f.Group(), f.Value(), read.Gammas[f], g)
+		}
+	}
+}
+
+func TestReadModelError(t *testing.T) {
+
+	cases := map[string]string{
+		"header": "size 3\n",
+		"even":   "patternsize 4\n",
+		"large":  "patternsize 27\n",
+		"gamma":  "patternsize 3\n0 zz 1\n",
+	}
+
+	for k, tc := range cases {
+		if _, err := ReadModel(strings.NewReader(tc)); err == nil {
+			t.Errorf("%s: read without error", k)
+		}
+	}
+}
+
+// rowMajor predicts empty points from the top left corner.
+type rowMajor struct{}
+
+func (rowMajor) Predict(bd *board.Board, isBlack bool) []int {
+
+	var r []int
+
+	for y := 0; y < bd.Size(); y++ {
+		for x := 0; x < bd.Size(); x++ {
+			if pt := bd.Point(x, y); bd.IsEmpty(pt) {
+				r = append(r, pt)
+			}
+		}
+	}
+
+	return r
+}
+
+func TestEvaluation(t *testing.T) {
+
+	roots, err := sgf.Parse("(;SZ[5];B[aa];W[ba];B[];W[ea];B[ee])")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	e := NewEvaluation(rowMajor{}, 3, []int{3})
+
+	if err := e.AddGame(roots[0]); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	// Ranks 1, 1 in the first phase, pass skipped, ranks 3 and 22 in the second.
+	if e.Total.Positions != 4 || e.Total.RankSum != 27 {
+		t.Errorf("%d positions rank sum %d, expected 4 and 27", e.Total.Positions, e.Total.RankSum)
+	}
+
+	cases := map[string]struct {
+		stats    Stats
+		k        int
+		expected float64
+	}{
+		"top-1":         {e.Total, 1, 0.5},
+		"top-3":         {e.Total, 3, 0.75},
+		"opening top-1": {e.ByPhase[0], 1, 1},
+		"later top-1":   {e.ByPhase[1], 1, 0},
+		"later top-3":   {e.ByPhase[1], 3, 0.5},
+	}
+
+	for k, tc := range cases {
+		if actual := tc.stats.Accuracy(tc.k); actual != tc.expected {
+			t.Errorf("%s: %v, expected %v", k, actual, tc.expected)
+		}
+	}
+
+	if r := e.ByPhase[1].MeanRank(); r != 12.5 {
+		t.Errorf("mean rank %v, expected 12.5", r)
+	}
+}
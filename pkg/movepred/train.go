@@ -0,0 +1,172 @@
+package movepred
+
+import (
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/sgf"
+)
+
+// A Trainer collects positions from games and fits a Model to them.
+type Trainer struct {
+	extractor Extractor
+
+	// Features are numbered in order of first appearance.
+	features []Feature
+	indices  map[Feature]int32
+
+	// wins[i] is the number of played moves having feature i.
+	wins []float64
+
+	positions []position
+}
+
+// A position is one competition between all legal moves.
+type position struct {
+
+	// Features of each candidate, played move first.
+	candidates [][]int32
+}
+
+// NewTrainer create a Trainer object.
+func NewTrainer(e Extractor) Trainer {
+
+	return Trainer{
+		extractor: e,
+		indices:   map[Feature]int32{},
+	}
+}
+
+// NumPositions returns the number of positions collected.
+func (t *Trainer) NumPositions() int {
+
+	return len(t.positions)
+}
+
+// AddGame collects every move of the main line of a game.
+func (t *Trainer) AddGame(root *sgf.Node) error {
+
+	return sgf.Replay(root, func(bd *board.Board, m sgf.Move) error {
+
+		if !m.Pass {
+			t.AddPosition(bd, bd.Point(m.X, m.Y), m.Black)
+		}
+
+		return nil
+	})
+}
+
+// AddPosition collects a position where the expert played pt. It does
+// nothing if pt is illegal.
+func (t *Trainer) AddPosition(bd *board.Board, played int, isBlack bool) {
+
+	winner := t.extractor.Extract(bd, played, isBlack)
+	if winner == nil {
+		return
+	}
+
+	p := position{candidates: [][]int32{t.index(winner)}}
+
+	for y := 0; y < bd.Size(); y++ {
+		for x := 0; x < bd.Size(); x++ {
+
+			pt := bd.Point(x, y)
+
+			if pt == played || !bd.IsEmpty(pt) {
+				continue
+			}
+
+			if fs := t.extractor.Extract(bd, pt, isBlack); fs != nil {
+				p.candidates = append(p.candidates, t.index(fs))
+			}
+		}
+	}
+
+	for _, i := range p.candidates[0] {
+		t.wins[i]++
+	}
+
+	t.positions = append(t.positions, p)
+}
+
+func (t *Trainer) index(fs []Feature) []int32 {
+
+	r := make([]int32, len(fs))
+
+	for k, f := range fs {
+
+		i, ok := t.indices[f]
+		if !ok {
+
+			i = int32(len(t.features))
+
+			t.indices[f] = i
+			t.features = append(t.features, f)
+			t.wins = append(t.wins, 0)
+		}
+
+		r[k] = i
+	}
+
+	return r
+}
+
+// Train runs iterations of minorization-maximization, updating one feature
+// group at a time, and returns the fitted model. Each gamma has a prior of
+// one win and one loss against a virtual opponent of strength 1 so unseen
+// or always-played features stay finite.
+func (t *Trainer) Train(iterations int) Model {
+
+	gammas := make([]float64, len(t.features))
+	for i := range gammas {
+		gammas[i] = 1
+	}
+
+	denominators := make([]float64, len(t.features))
+
+	for it := 0; it < iterations; it++ {
+		for g := uint8(0); g < numGroups; g++ {
+
+			for i := range denominators {
+				denominators[i] = 0
+			}
+
+			for _, p := range t.positions {
+
+				total := 0.0
+				strengths := make([]float64, len(p.candidates))
+
+				for k, c := range p.candidates {
+
+					s := 1.0
+					for _, i := range c {
+						s *= gammas[i]
+					}
+
+					strengths[k] = s
+					total += s
+				}
+
+				for k, c := range p.candidates {
+					for _, i := range c {
+						if t.features[i].Group() == g {
+							denominators[i] += strengths[k] / gammas[i] / total
+						}
+					}
+				}
+			}
+
+			for i, f := range t.features {
+				if f.Group() == g {
+					gammas[i] = (t.wins[i] + 1) / (denominators[i] + 2/(gammas[i]+1))
+				}
+			}
+		}
+	}
+
+	m := NewModel(t.extractor)
+
+	for i, f := range t.features {
+		m.Gammas[f] = gammas[i]
+	}
+
+	return m
+}
@@ -0,0 +1,279 @@
+/*
+Package patterndb provides a library for counting how often Go patterns are played.
+
+Patterns of several sizes are taken around every legal move of every position
+of a game collection. Each pattern is counted as seen, and as played if the
+expert chose that move. Patterns are keyed by size and a hash seen from the
+player to move, so mirrored and rotated patterns share counts, and so do color
+reversed patterns met by the other color.
+
+The file format is little endian and versioned.
+
+	magic    "GXPD"
+	version  uint16
+	count    uint32
+	records  count times, sorted by size then hash
+	  size   uint8
+	  hash   uint64
+	  seen   uvarint
+	  played uvarint
+*/
+package patterndb
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/hash"
+	"github.com/gosharplite/goxit/pkg/internal/countwriter"
+	"github.com/gosharplite/goxit/pkg/sgf"
+)
+
+const (
+	magic   = "GXPD"
+	version = 2
+)
+
+// A Key identifies a pattern by size and the GetRelativeHash64 of the player
+// to move.
+type Key struct {
+	Size int
+	Hash uint64
+}
+
+// An Entry counts a pattern.
+type Entry struct {
+
+	// Number of legal moves with this pattern.
+	Seen uint64
+
+	// Number of those moves which were played.
+	Played uint64
+}
+
+// Rate returns the fraction of times a pattern was played when seen.
+func (e Entry) Rate() float64 {
+
+	if e.Seen == 0 {
+		return 0
+	}
+
+	return float64(e.Played) / float64(e.Seen)
+}
+
+// A DB contains pattern counts.
+type DB struct {
+
+	// Pattern sizes extracted around each move, odd and at most
+	// hash.MaxSize.
+	Sizes []int
+
+	Entries map[Key]Entry
+}
+
+// New create a DB object extracting patterns of the given sizes.
+func New(sizes ...int) (*DB, error) {
+
+	for _, s := range sizes {
+		if err := checkSize(s); err != nil {
+			return nil, err
+		}
+	}
+
+	return &DB{
+		Sizes:   sizes,
+		Entries: map[Key]Entry{},
+	}, nil
+}
+
+// checkSize returns an error if patterns of a size cannot be extracted.
+func checkSize(size int) error {
+
+	if size < 1 || size%2 == 0 || size > hash.MaxSize {
+		return fmt.Errorf("patterndb: pattern size %d is not odd and between 1 and %d", size, hash.MaxSize)
+	}
+
+	return nil
+}
+
+// Lookup returns the counts of a pattern, hash being its GetRelativeHash64
+// for the player to move.
+func (db *DB) Lookup(size int, hash uint64) (Entry, bool) {
+
+	e, ok := db.Entries[Key{size, hash}]
+
+	return e, ok
+}
+
+// AddGame counts every position of the main line of a game.
+func (db *DB) AddGame(root *sgf.Node) error {
+
+	return sgf.Replay(root, func(bd *board.Board, m sgf.Move) error {
+
+		if !m.Pass {
+			db.AddPosition(bd, bd.Point(m.X, m.Y), m.Black)
+		}
+
+		return nil
+	})
+}
+
+// AddPosition counts the patterns around every legal move of a color,
+// marking the one at played as played.
+func (db *DB) AddPosition(bd *board.Board, played int, isBlack bool) {
+
+	for y := 0; y < bd.Size(); y++ {
+		for x := 0; x < bd.Size(); x++ {
+
+			pt := bd.Point(x, y)
+
+			if !bd.IsEmpty(pt) || !isLegal(bd, pt, isBlack) {
+				continue
+			}
+
+			for _, s := range db.Sizes {
+
+				p := bd.Pattern(pt, s)
+				k := newKey(&p, isBlack)
+
+				e := db.Entries[k]
+
+				e.Seen++
+				if pt == played {
+					e.Played++
+				}
+
+				db.Entries[k] = e
+			}
+		}
+	}
+}
+
+// newKey returns the key of a pattern met by a color.
+func newKey(p *hash.Pattern, isBlack bool) Key {
+
+	return Key{p.Size(), p.GetRelativeHash64(isBlack)}
+}
+
+// Prune removes patterns seen fewer than min times.
+func (db *DB) Prune(min uint64) {
+
+	for k, e := range db.Entries {
+		if e.Seen < min {
+			delete(db.Entries, k)
+		}
+	}
+}
+
+// WriteTo writes the database in the binary file format.
+func (db *DB) WriteTo(w io.Writer) (int64, error) {
+
+	cw := countwriter.New(w)
+
+	keys := make([]Key, 0, len(db.Entries))
+	for k := range db.Entries {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+
+		if keys[i].Size != keys[j].Size {
+			return keys[i].Size < keys[j].Size
+		}
+
+		return keys[i].Hash < keys[j].Hash
+	})
+
+	cw.Put([]byte(magic))
+	cw.Put(binary.LittleEndian.AppendUint16(nil, version))
+	cw.Put(binary.LittleEndian.AppendUint32(nil, uint32(len(keys))))
+
+	buf := make([]byte, 0, 1+8+2*binary.MaxVarintLen64)
+
+	for _, k := range keys {
+
+		e := db.Entries[k]
+
+		buf = append(buf[:0], uint8(k.Size))
+		buf = binary.LittleEndian.AppendUint64(buf, k.Hash)
+		buf = binary.AppendUvarint(buf, e.Seen)
+		buf = binary.AppendUvarint(buf, e.Played)
+
+		cw.Put(buf)
+	}
+
+	return cw.Flush()
+}
+
+// Read reads a database written by WriteTo. Sizes holds every size found.
+func Read(r io.Reader) (*DB, error) {
+
+	br := bufio.NewReader(r)
+
+	header := make([]byte, len(magic)+2+4)
+
+	if _, err := io.ReadFull(br, header); err != nil {
+		return nil, fmt.Errorf("patterndb: reading header: %v", err)
+	}
+
+	if string(header[:len(magic)]) != magic {
+		return nil, errors.New("patterndb: not a pattern database")
+	}
+
+	if v := binary.LittleEndian.Uint16(header[len(magic):]); v != version {
+		return nil, fmt.Errorf("patterndb: unsupported version %d", v)
+	}
+
+	count := binary.LittleEndian.Uint32(header[len(magic)+2:])
+
+	db := &DB{Entries: map[Key]Entry{}}
+	sizes := map[int]bool{}
+
+	record := make([]byte, 9)
+
+	for i := uint32(0); i < count; i++ {
+
+		if _, err := io.ReadFull(br, record); err != nil {
+			return nil, fmt.Errorf("patterndb: reading record %d: %v", i, err)
+		}
+
+		seen, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("patterndb: reading record %d: %v", i, err)
+		}
+
+		played, err := binary.ReadUvarint(br)
+		if err != nil {
+			return nil, fmt.Errorf("patterndb: reading record %d: %v", i, err)
+		}
+
+		k := Key{int(record[0]), binary.LittleEndian.Uint64(record[1:])}
+
+		if err := checkSize(k.Size); err != nil {
+			return nil, fmt.Errorf("patterndb: reading record %d: %v", i, err)
+		}
+
+		db.Entries[k] = Entry{Seen: seen, Played: played}
+
+		if !sizes[k.Size] {
+			sizes[k.Size] = true
+			db.Sizes = append(db.Sizes, k.Size)
+		}
+	}
+
+	return db, nil
+}
+
+func isLegal(bd *board.Board, pt int, isBlack bool) bool {
+
+	if isBlack {
+		return bd.IsLegalBlack(pt) == nil
+	}
+
+	return bd.IsLegalWhite(pt) == nil
+}
@@ -0,0 +1,163 @@
+package patterndb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/board"
+	"github.com/gosharplite/goxit/pkg/hash"
+	"github.com/gosharplite/goxit/pkg/sgf"
+)
+
+func TestAddPosition(t *testing.T) {
+
+	bh := board.NewBoard(3)
+
+	db, err := New(3)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	db.AddPosition(&bh, bh.Point(1, 1), true)
+
+	seen := uint64(0)
+	for _, e := range db.Entries {
+		seen += e.Seen
+	}
+
+	if seen != 9 {
+		t.Errorf("%d patterns seen, expected 9", seen)
+	}
+
+	p := bh.Pattern(bh.Point(1, 1), 3)
+
+	e, ok := db.Lookup(3, p.GetRelativeHash64(true))
+	if !ok || e.Seen != 1 || e.Played != 1 {
+		t.Errorf("centre %+v, expected seen 1 played 1", e)
+	}
+
+	if e.Rate() != 1 {
+		t.Errorf("centre rate %v, expected 1", e.Rate())
+	}
+
+	p = bh.Pattern(bh.Point(0, 0), 3)
+
+	e, ok = db.Lookup(3, p.GetRelativeHash64(true))
+	if !ok || e.Played != 0 {
+		t.Errorf("corner %+v, expected played 0", e)
+	}
+}
+
+func TestKeyTransforms(t *testing.T) {
+
+	p, err := hash.ParsePattern(`
+	X..##
+	.OX##
+	..O##
+	.X.##
+	#####`)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	k := newKey(&p, true)
+
+	// A transform met by the color matching its colors.
+	for i := 0; i < 16; i++ {
+
+		sym, swapped := hash.Symmetry(i%8), i >= 8
+
+		q := p.Transform(sym, swapped)
+
+		if tk := newKey(&q, !swapped); tk != k {
+			t.Errorf("symmetry %d swapped %v: key %+v, expected %+v", sym, swapped, tk, k)
+		}
+	}
+}
+
+func TestNewError(t *testing.T) {
+
+	cases := map[string][]int{
+		"zero":  {0},
+		"even":  {3, 4},
+		"large": {27},
+	}
+
+	for k, tc := range cases {
+		if _, err := New(tc...); err == nil {
+			t.Errorf("%s: created without error", k)
+		}
+	}
+}
+
+func TestReadWrite(t *testing.T) {
+
+	roots, err := sgf.Parse("(;SZ[9];B[ee];W[cc];B[gc];W[cg];B[gg])")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	db, err := New(3, 5)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if err := db.AddGame(roots[0]); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	var buf bytes.Buffer
+
+	n, err := db.WriteTo(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if n != int64(buf.Len()) {
+		t.Errorf("wrote %d bytes, reported %d", buf.Len(), n)
+	}
+
+	read, err := Read(&buf)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(read.Sizes) != 2 || len(read.Entries) != len(db.Entries) {
+		t.Fatalf("read %d sizes %d patterns, expected 2 and %d", len(read.Sizes), len(read.Entries), len(db.Entries))
+	}
+
+	for k, e := range db.Entries {
+		if read.Entries[k] != e {
+			t.Errorf("%+v: %+v, expected %+v", k, read.Entries[k], e)
+		}
+	}
+
+	played := uint64(0)
+	for k, e := range read.Entries {
+		if k.Size == 5 {
+			played += e.Played
+		}
+	}
+
+	if played != 5 {
+		t.Errorf("%d moves played, expected 5", played)
+	}
+}
+
+func TestReadError(t *testing.T) {
+
+	cases := map[string][]byte{
+		"empty":     {},
+		"magic":     []byte("ABCD\x01\x00\x00\x00\x00\x00"),
+		"old":       []byte("GXPD\x01\x00\x00\x00\x00\x00"),
+		"version":   []byte("GXPD\x09\x00\x00\x00\x00\x00"),
+		"truncated": []byte("GXPD\x02\x00\x01\x00\x00\x00\x03"),
+		"size":      []byte("GXPD\x02\x00\x01\x00\x00\x00\x1b\x00\x00\x00\x00\x00\x00\x00\x00\x01\x01"),
+	}
+
+	for k, tc := range cases {
+		if _, err := Read(bytes.NewReader(tc)); err == nil {
+			t.Errorf("%s: read without error", k)
+		}
+	}
+}
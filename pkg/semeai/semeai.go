@@ -0,0 +1,324 @@
+/*
+Package semeai provides a depth-first proof-number search for capturing races.
+
+The attacker wins by capturing the defender's chain before its own chain is
+captured. The defender wins by capturing the attacker's chain, or by keeping
+its chain on the board when the attacker runs out of moves; the defender may
+pass, the attacker may not. Moves are the liberties of the two chains and of
+weak chains next to them.
+
+Proof and disproof numbers of new positions are estimated from liberty
+counts: a chain with many liberties is hard to capture.
+*/
+package semeai
+
+import (
+	"errors"
+
+	"github.com/gosharplite/goxit/pkg/board"
+)
+
+// A Result is the outcome of a race.
+type Result int
+
+// Results of a race.
+const (
+	Unknown Result = iota
+	Capture
+	Escape
+)
+
+func (r Result) String() string {
+
+	switch r {
+	case Capture:
+		return "capture"
+	case Escape:
+		return "escape"
+	}
+
+	return "unknown"
+}
+
+// Pass is the move of a defender who passes.
+const Pass = 0
+
+const infinity = 1 << 30
+
+// A Solver solves capturing races.
+type Solver struct {
+
+	// Number of positions searched before giving up.
+	MaxNodes int
+
+	// Number of positions searched by the last Solve.
+	Nodes int
+
+	attacker int
+	defender int
+
+	// Color of the attacker, the chain on attacker may be captured.
+	attackerBlack bool
+
+	// Weak chains have at most this many liberties, see moves.
+	weak int
+
+	table map[uint64]entry
+}
+
+// entry holds the proof and disproof numbers of a position for the player
+// to move.
+type entry struct {
+	phi   int
+	delta int
+}
+
+// NewSolver create a Solver object.
+func NewSolver() *Solver {
+
+	return &Solver{
+		MaxNodes: 100000,
+		weak:     2,
+	}
+}
+
+// Solve decides whether the chain on attacker captures the chain on defender,
+// the attacker moving first if attackerFirst is true. It returns the result
+// and a winning first move of the first player, 0 if there is none. The board
+// is restored before returning.
+func (s *Solver) Solve(bd *board.Board, attacker, defender int, attackerFirst bool) (Result, int, error) {
+
+	if bd.IsEmpty(attacker) || bd.IsEmpty(defender) {
+		return Unknown, 0, errors.New("semeai: no stone on a chain")
+	}
+
+	if bd.IsBlack(attacker) == bd.IsBlack(defender) {
+		return Unknown, 0, errors.New("semeai: chains of the same color")
+	}
+
+	s.attacker = attacker
+	s.defender = defender
+	s.attackerBlack = bd.IsBlack(attacker)
+	s.table = map[uint64]entry{}
+	s.Nodes = 0
+
+	e := s.mid(bd, attackerFirst, infinity-1, infinity-1)
+
+	var r Result
+
+	switch {
+	case e.phi == 0 && attackerFirst, e.delta == 0 && !attackerFirst:
+		r = Capture
+	case e.delta == 0 && attackerFirst, e.phi == 0 && !attackerFirst:
+		r = Escape
+	default:
+		return Unknown, 0, nil
+	}
+
+	if e.phi != 0 {
+		return r, 0, nil
+	}
+
+	// A move to a position lost for the opponent.
+	for _, pt := range s.moves(bd, attackerFirst) {
+
+		if !s.play(bd, pt, attackerFirst) {
+			continue
+		}
+
+		c := s.lookup(bd, !attackerFirst)
+
+		bd.Undo()
+
+		if c.delta == 0 {
+			return r, pt, nil
+		}
+	}
+
+	return r, 0, nil
+}
+
+// mid searches a position until its proof number reaches thphi or its
+// disproof number reaches thdelta, for the player to move.
+func (s *Solver) mid(bd *board.Board, attackerToPlay bool, thphi, thdelta int) entry {
+
+	s.Nodes++
+
+	key := s.key(bd, attackerToPlay)
+
+	e := s.lookup(bd, attackerToPlay)
+	if e.phi == 0 || e.delta == 0 {
+		return e
+	}
+
+	// Children are stored before they are searched.
+	var children []int
+	var keys []uint64
+
+	for _, pt := range s.moves(bd, attackerToPlay) {
+
+		if !s.play(bd, pt, attackerToPlay) {
+			continue
+		}
+
+		children = append(children, pt)
+		keys = append(keys, s.key(bd, !attackerToPlay))
+
+		s.lookup(bd, !attackerToPlay)
+
+		bd.Undo()
+	}
+
+	for {
+
+		// The player to move wins if a child is lost for the opponent, and
+		// loses if every child is won for the opponent.
+		e = entry{phi: infinity, delta: 0}
+
+		best, phi2 := -1, infinity
+
+		for i, k := range keys {
+
+			c := s.table[k]
+
+			e.delta = min(e.delta+c.phi, infinity)
+
+			if c.delta < e.phi {
+				phi2 = e.phi
+				e.phi = c.delta
+				best = i
+			} else if c.delta < phi2 {
+				phi2 = c.delta
+			}
+		}
+
+		s.table[key] = e
+
+		if e.phi >= thphi || e.delta >= thdelta || s.Nodes >= s.MaxNodes {
+			return e
+		}
+
+		c := s.table[keys[best]]
+
+		pt := children[best]
+
+		s.play(bd, pt, attackerToPlay)
+
+		s.mid(bd, !attackerToPlay, thdelta+c.phi-e.delta, min(thphi, phi2+1))
+
+		bd.Undo()
+	}
+}
+
+// lookup returns the numbers of the position, estimating them if it is new.
+func (s *Solver) lookup(bd *board.Board, attackerToPlay bool) entry {
+
+	key := s.key(bd, attackerToPlay)
+
+	if e, ok := s.table[key]; ok {
+		return e
+	}
+
+	e := s.evaluate(bd, attackerToPlay)
+
+	s.table[key] = e
+
+	return e
+}
+
+// evaluate returns the numbers of a position for the player to move.
+func (s *Solver) evaluate(bd *board.Board, attackerToPlay bool) entry {
+
+	won := entry{phi: 0, delta: infinity}
+	lost := entry{phi: infinity, delta: 0}
+
+	switch {
+	case bd.IsEmpty(s.defender) && attackerToPlay, bd.IsEmpty(s.attacker) && !attackerToPlay:
+		return won
+	case bd.IsEmpty(s.defender), bd.IsEmpty(s.attacker):
+		return lost
+	}
+
+	own, other := s.attacker, s.defender
+	if !attackerToPlay {
+		own, other = other, own
+	}
+
+	// Filling the opponent's liberties proves, keeping one's own disproves.
+	return entry{
+		phi:   max(1, bd.NumLiberties(other)),
+		delta: max(1, bd.NumLiberties(own)),
+	}
+}
+
+// moves returns the liberties of the two chains and of the chains next to
+// them with at most weak liberties, then a pass for the defender.
+func (s *Solver) moves(bd *board.Board, attackerToPlay bool) []int {
+
+	var r []int
+
+	seen := map[int]bool{}
+
+	add := func(pts []int) {
+		for _, pt := range pts {
+			if !seen[pt] {
+				seen[pt] = true
+				r = append(r, pt)
+			}
+		}
+	}
+
+	for _, c := range []int{s.defender, s.attacker} {
+
+		add(bd.Liberties(c))
+
+		// Chains of the other color touching the chain.
+		for _, pt := range bd.Chain(c) {
+			for _, n := range bd.Neighbors(pt) {
+
+				if bd.IsEmpty(n) || !bd.IsBlack(n) && !bd.IsWhite(n) || bd.IsBlack(n) == bd.IsBlack(c) {
+					continue
+				}
+
+				if bd.NumLiberties(n) <= s.weak {
+					add(bd.Liberties(n))
+				}
+			}
+		}
+	}
+
+	if !attackerToPlay {
+		r = append(r, Pass)
+	}
+
+	return r
+}
+
+// play plays a move of the player to move, reporting whether it is legal. A
+// pass is played on the board too, ending any ko ban, and taken back with
+// Undo like a move.
+func (s *Solver) play(bd *board.Board, pt int, attackerToPlay bool) bool {
+
+	isBlack := s.isBlack(attackerToPlay)
+
+	switch {
+	case pt != Pass:
+		return bd.Do(pt, isBlack) == nil
+	case isBlack:
+		return bd.PassBlack() == nil
+	}
+
+	return bd.PassWhite() == nil
+}
+
+// isBlack returns the color of the player to move.
+func (s *Solver) isBlack(attackerToPlay bool) bool {
+
+	return s.attackerBlack == attackerToPlay
+}
+
+// key identifies a position with the player to move and the ko point.
+func (s *Solver) key(bd *board.Board, attackerToPlay bool) uint64 {
+
+	return bd.Key(s.isBlack(attackerToPlay))
+}
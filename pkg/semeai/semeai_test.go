@@ -0,0 +1,223 @@
+package semeai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/board"
+)
+
+// races are set in the top left corner of a 9x9 board. The black chain on
+// the third line races the white chain on the fourth; the stones on the right
+// are strong.
+var races = map[string]struct {
+	diagram       string
+	attacker      [2]int
+	defender      [2]int
+	attackerFirst bool
+	result        Result
+	first         [][2]int
+}{
+	"three each, attacker first": {
+		diagram: `
+		.........
+		...OO....
+		XXXXO....
+		OOOOX....
+		...XX....`,
+		attacker:      [2]int{0, 2},
+		defender:      [2]int{0, 3},
+		attackerFirst: true,
+		result:        Capture,
+		first:         [][2]int{{0, 4}, {1, 4}, {2, 4}},
+	},
+	"three each, defender first": {
+		diagram: `
+		.........
+		...OO....
+		XXXXO....
+		OOOOX....
+		...XX....`,
+		attacker: [2]int{0, 2},
+		defender: [2]int{0, 3},
+		result:   Escape,
+		first:    [][2]int{{0, 1}, {1, 1}, {2, 1}},
+	},
+	"three against two, defender first": {
+		diagram: `
+		.........
+		...OO....
+		XXXXO....
+		OOOOX....
+		..XXX....`,
+		attacker: [2]int{0, 2},
+		defender: [2]int{0, 3},
+		result:   Capture,
+	},
+	"white attacks": {
+		diagram: `
+		.........
+		...OO....
+		XXXXO....
+		OOOOX....
+		...XX....`,
+		attacker:      [2]int{0, 3},
+		defender:      [2]int{0, 2},
+		attackerFirst: true,
+		result:        Capture,
+		first:         [][2]int{{0, 1}, {1, 1}, {2, 1}},
+	},
+}
+
+// setup places a diagram in the top left corner.
+func setup(t *testing.T, bd *board.Board, diagram string) {
+
+	for y, row := range strings.Fields(diagram) {
+		for x, c := range row {
+
+			pt := bd.Point(x, y)
+
+			var err error
+
+			switch c {
+			case 'X':
+				err = bd.DoBlack(pt)
+			case 'O':
+				err = bd.DoWhite(pt)
+			}
+
+			if err != nil {
+				t.Fatalf("(%d, %d): %v", x, y, err)
+			}
+		}
+	}
+}
+
+func TestSolve(t *testing.T) {
+
+	for k, tc := range races {
+
+		bd := board.NewBoard(9)
+
+		setup(t, &bd, tc.diagram)
+		before := bd.String()
+
+		attacker := bd.Point(tc.attacker[0], tc.attacker[1])
+		defender := bd.Point(tc.defender[0], tc.defender[1])
+
+		r, pt, err := NewSolver().Solve(&bd, attacker, defender, tc.attackerFirst)
+		if err != nil {
+			t.Fatalf("%s: %v", k, err)
+		}
+
+		if r != tc.result {
+			t.Errorf("%s: %v, expected %v", k, r, tc.result)
+		}
+
+		if tc.first == nil {
+			if pt != 0 {
+				t.Errorf("%s: winning move for the loser", k)
+			}
+		} else if x, y := bd.XY(pt); !containsXY(tc.first, x, y) {
+			t.Errorf("%s: first move (%d, %d), expected one of %v", k, x, y, tc.first)
+		}
+
+		if bd.String() != before {
+			t.Errorf("%s: board not restored\n%s", k, bd.String())
+		}
+	}
+}
+
+func TestMaxNodes(t *testing.T) {
+
+	bd := board.NewBoard(9)
+
+	setup(t, &bd, races["three each, attacker first"].diagram)
+
+	s := NewSolver()
+	s.MaxNodes = 1
+
+	r, _, err := s.Solve(&bd, bd.Point(0, 2), bd.Point(0, 3), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r != Unknown {
+		t.Errorf("%v with one node", r)
+	}
+
+	if s.Nodes > s.MaxNodes {
+		t.Errorf("searched %d nodes, limit %d", s.Nodes, s.MaxNodes)
+	}
+}
+
+func TestPassEndsKo(t *testing.T) {
+
+	bd := board.NewBoard(9)
+
+	setup(t, &bd, `
+		.XO.
+		XO.O
+		.XO.`)
+
+	// Black takes the ko.
+	if err := bd.DoBlack(bd.Point(2, 1)); err != nil {
+		t.Fatal(err.Error())
+	}
+
+	ko := bd.KoPoint()
+	if ko != bd.Point(1, 1) {
+		t.Fatalf("ko point %d, expected %d", ko, bd.Point(1, 1))
+	}
+
+	before := bd.String()
+	key := bd.Key(true)
+
+	s := NewSolver()
+	s.attackerBlack = true
+
+	// White, the defender, passes.
+	if !s.play(&bd, Pass, false) {
+		t.Fatal("pass not played")
+	}
+
+	if bd.KoPoint() != 0 || bd.Key(true) == key {
+		t.Errorf("ko ban kept after a pass")
+	}
+
+	bd.Undo()
+
+	if bd.KoPoint() != ko || bd.String() != before {
+		t.Errorf("pass not taken back\n%s", bd.String())
+	}
+}
+
+func TestSolveError(t *testing.T) {
+
+	bd := board.NewBoard(9)
+
+	setup(t, &bd, `
+		XO.
+		X..`)
+
+	s := NewSolver()
+
+	if _, _, err := s.Solve(&bd, bd.Point(0, 0), bd.Point(2, 0), true); err == nil {
+		t.Error("solved without a defender stone")
+	}
+
+	if _, _, err := s.Solve(&bd, bd.Point(0, 0), bd.Point(0, 1), true); err == nil {
+		t.Error("solved chains of the same color")
+	}
+}
+
+func containsXY(pts [][2]int, x, y int) bool {
+
+	for _, p := range pts {
+		if p[0] == x && p[1] == y {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,431 @@
+/*
+Package sgf provides a library for reading Go games in Smart Game Format.
+
+	(;GM[1]SZ[9];B[ee];W[ce](;B[cc])(;B[gc]))
+
+A collection holds one or more game trees. Each node holds properties and
+child nodes; the first child is the main line and others are variations.
+*/
+package sgf
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gosharplite/goxit/pkg/board"
+)
+
+// A Node contains the properties of a game tree node.
+type Node struct {
+	Properties map[string][]string
+
+	Parent   *Node
+	Children []*Node
+}
+
+// A Move is a stone played in a node. Pass is true if X and Y are not used.
+type Move struct {
+	X, Y  int
+	Black bool
+	Pass  bool
+}
+
+// Parse reads a collection of game trees and returns their root nodes.
+func Parse(data string) ([]*Node, error) {
+
+	p := parser{data: data}
+
+	var roots []*Node
+
+	for {
+		p.skipSpace()
+
+		if p.pos >= len(p.data) {
+			break
+		}
+
+		if p.data[p.pos] != '(' {
+			return nil, p.error("expected '('")
+		}
+
+		root, err := p.tree(nil)
+		if err != nil {
+			return nil, err
+		}
+
+		roots = append(roots, root)
+	}
+
+	if len(roots) == 0 {
+		return nil, errors.New("sgf: no game tree")
+	}
+
+	return roots, nil
+}
+
+// ParseFile reads a collection of game trees from a file.
+func ParseFile(path string) ([]*Node, error) {
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(string(b))
+}
+
+// Files returns the paths of all .sgf files under a directory, sorted.
+func Files(dir string) ([]string, error) {
+
+	var r []string
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && strings.EqualFold(filepath.Ext(path), ".sgf") {
+			r = append(r, path)
+		}
+
+		return nil
+	})
+
+	return r, err
+}
+
+// Value returns the first value of a property, empty if it does not exist.
+func (n *Node) Value(id string) string {
+
+	v := n.Properties[id]
+	if len(v) == 0 {
+		return ""
+	}
+
+	return v[0]
+}
+
+// Size returns the board size of the game, 19 if not specified.
+func (n *Node) Size() int {
+
+	for ; n.Parent != nil; n = n.Parent {
+	}
+
+	s, err := strconv.Atoi(n.Value("SZ"))
+	if err != nil {
+		return 19
+	}
+
+	return s
+}
+
+// Move returns the move played in a node. ok is false if there is none.
+func (n *Node) Move() (m Move, ok bool) {
+
+	v, isBlack := n.Properties["B"]
+	if !isBlack {
+		if v, ok = n.Properties["W"]; !ok {
+			return m, false
+		}
+	}
+
+	m.Black = isBlack
+
+	if len(v) == 0 {
+		m.Pass = true
+		return m, true
+	}
+
+	x, y, ok := point(v[0], n.Size())
+	if !ok {
+		m.Pass = true
+		return m, true
+	}
+
+	m.X = x
+	m.Y = y
+
+	return m, true
+}
+
+// Setup returns the coordinates of stones added by AB and AW.
+func (n *Node) Setup() (black, white [][2]int) {
+
+	size := n.Size()
+
+	return points(n.Properties["AB"], size), points(n.Properties["AW"], size)
+}
+
+// MainLine returns the node and the first children below it.
+func (n *Node) MainLine() []*Node {
+
+	r := []*Node{n}
+
+	for len(n.Children) > 0 {
+		n = n.Children[0]
+		r = append(r, n)
+	}
+
+	return r
+}
+
+// Replay plays the main line of a game on a new board. fn is called before
+// each move, with the board in the position the move was played from, and
+// replay stops at the first error. AB and AW stones are setup stones.
+func Replay(root *Node, fn func(bd *board.Board, m Move) error) error {
+
+	bd := board.NewBoard(root.Size())
+
+	for _, n := range root.MainLine() {
+
+		black, white := n.Setup()
+
+		for _, p := range black {
+			if err := bd.SetupBlack(bd.Point(p[0], p[1])); err != nil {
+				return err
+			}
+		}
+
+		for _, p := range white {
+			if err := bd.SetupWhite(bd.Point(p[0], p[1])); err != nil {
+				return err
+			}
+		}
+
+		m, ok := n.Move()
+		if !ok {
+			continue
+		}
+
+		if err := fn(&bd, m); err != nil {
+			return err
+		}
+
+		var err error
+
+		switch {
+		case m.Pass && m.Black:
+			err = bd.PassBlack()
+		case m.Pass:
+			err = bd.PassWhite()
+		case m.Black:
+			err = bd.DoBlack(bd.Point(m.X, m.Y))
+		default:
+			err = bd.DoWhite(bd.Point(m.X, m.Y))
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// point converts a value such as "dd" to a coordinate.
+func point(v string, size int) (x, y int, ok bool) {
+
+	if len(v) != 2 {
+		return 0, 0, false
+	}
+
+	x = int(v[0] - 'a')
+	y = int(v[1] - 'a')
+
+	if x < 0 || x >= size || y < 0 || y >= size {
+		return 0, 0, false
+	}
+
+	return x, y, true
+}
+
+// points converts values, including compressed "aa:cc" rectangles, to coordinates.
+func points(vs []string, size int) [][2]int {
+
+	var r [][2]int
+
+	for _, v := range vs {
+
+		if i := strings.IndexByte(v, ':'); i >= 0 {
+
+			x1, y1, ok1 := point(v[:i], size)
+			x2, y2, ok2 := point(v[i+1:], size)
+
+			if !ok1 || !ok2 {
+				continue
+			}
+
+			for x := x1; x <= x2; x++ {
+				for y := y1; y <= y2; y++ {
+					r = append(r, [2]int{x, y})
+				}
+			}
+
+			continue
+		}
+
+		if x, y, ok := point(v, size); ok {
+			r = append(r, [2]int{x, y})
+		}
+	}
+
+	return r
+}
+
+type parser struct {
+	data string
+	pos  int
+}
+
+func (p *parser) error(msg string) error {
+
+	return errors.New("sgf: " + msg + " at offset " + strconv.Itoa(p.pos))
+}
+
+func (p *parser) skipSpace() {
+
+	for p.pos < len(p.data) && strings.IndexByte(" \t\r\n", p.data[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// tree reads "(" sequence { tree } ")" and returns its first node.
+func (p *parser) tree(parent *Node) (*Node, error) {
+
+	// Skip '('
+	p.pos++
+
+	var first *Node
+
+	last := parent
+
+	for {
+		p.skipSpace()
+
+		if p.pos >= len(p.data) {
+			return nil, p.error("unexpected end")
+		}
+
+		switch p.data[p.pos] {
+
+		case ';':
+			p.pos++
+
+			n, err := p.node(last)
+			if err != nil {
+				return nil, err
+			}
+
+			if first == nil {
+				first = n
+			}
+
+			last = n
+
+		case '(':
+			if first == nil {
+				return nil, p.error("empty sequence")
+			}
+
+			if _, err := p.tree(last); err != nil {
+				return nil, err
+			}
+
+		case ')':
+			p.pos++
+
+			if first == nil {
+				return nil, p.error("empty sequence")
+			}
+
+			return first, nil
+
+		default:
+			return nil, p.error("unexpected character")
+		}
+	}
+}
+
+// node reads the properties of a node and links it to its parent.
+func (p *parser) node(parent *Node) (*Node, error) {
+
+	n := &Node{
+		Properties: map[string][]string{},
+		Parent:     parent,
+	}
+
+	if parent != nil {
+		parent.Children = append(parent.Children, n)
+	}
+
+	for {
+		p.skipSpace()
+
+		start := p.pos
+
+		for p.pos < len(p.data) && p.data[p.pos] >= 'A' && p.data[p.pos] <= 'Z' {
+			p.pos++
+		}
+
+		if start == p.pos {
+			return n, nil
+		}
+
+		id := p.data[start:p.pos]
+
+		p.skipSpace()
+
+		if p.pos >= len(p.data) || p.data[p.pos] != '[' {
+			return nil, p.error("expected '['")
+		}
+
+		for p.pos < len(p.data) && p.data[p.pos] == '[' {
+
+			v, err := p.value()
+			if err != nil {
+				return nil, err
+			}
+
+			n.Properties[id] = append(n.Properties[id], v)
+
+			p.skipSpace()
+		}
+	}
+}
+
+// value reads "[" text "]" handling escaped characters.
+func (p *parser) value() (string, error) {
+
+	// Skip '['
+	p.pos++
+
+	var b strings.Builder
+
+	for p.pos < len(p.data) {
+
+		c := p.data[p.pos]
+		p.pos++
+
+		switch c {
+
+		case '\\':
+			if p.pos < len(p.data) {
+				b.WriteByte(p.data[p.pos])
+				p.pos++
+			}
+
+		case ']':
+			return b.String(), nil
+
+		default:
+			b.WriteByte(c)
+		}
+	}
+
+	return "", p.error("unterminated value")
+}
@@ -0,0 +1,102 @@
+package sgf
+
+import (
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/board"
+)
+
+func TestParse(t *testing.T) {
+
+	roots, err := Parse("(;GM[1]SZ[9]C[a \\] b];B[ee];W[ce](;B[cc])(;B[gc]))")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if len(roots) != 1 {
+		t.Fatalf("%d roots, expected 1", len(roots))
+	}
+
+	root := roots[0]
+
+	if root.Size() != 9 {
+		t.Errorf("size %d, expected 9", root.Size())
+	}
+
+	if c := root.Value("C"); c != "a ] b" {
+		t.Errorf("comment %q, expected %q", c, "a ] b")
+	}
+
+	line := root.MainLine()
+	if len(line) != 4 {
+		t.Fatalf("main line has %d nodes, expected 4", len(line))
+	}
+
+	if n := len(line[2].Children); n != 2 {
+		t.Errorf("%d variations, expected 2", n)
+	}
+
+	m, ok := line[3].Move()
+	if !ok || !m.Black || m.X != 2 || m.Y != 2 {
+		t.Errorf("move %+v, expected black (2, 2)", m)
+	}
+}
+
+func TestParseError(t *testing.T) {
+
+	cases := map[string]string{
+		"empty":        "",
+		"unterminated": "(;B[aa]",
+		"no bracket":   "(;B)",
+		"no node":      "()",
+	}
+
+	for k, tc := range cases {
+		if _, err := Parse(tc); err == nil {
+			t.Errorf("%s: %q parsed without error", k, tc)
+		}
+	}
+}
+
+func TestReplay(t *testing.T) {
+
+	roots, err := Parse("(;SZ[3]AB[aa:ab];W[cc];B[];W[ba])")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	moves := 0
+	passes := 0
+
+	err = Replay(roots[0], func(bd *board.Board, m Move) error {
+
+		// Setup stones are not moves and passes change the player to move.
+		if bd.BlackToPlay() != m.Black {
+			t.Errorf("move %d: black to play %v", moves+1, bd.BlackToPlay())
+		}
+
+		if d := bd.Depth(); !bd.IsSetup(d) {
+			if pt, _ := bd.Move(d); moves == 2 && pt != 0 {
+				t.Errorf("last move %d, expected a pass", pt)
+			}
+		} else if moves != 0 {
+			t.Errorf("move %d follows a setup stone", moves+1)
+		}
+
+		moves++
+
+		if m.Pass {
+			passes++
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+
+	if moves != 3 || passes != 1 {
+		t.Errorf("%d moves %d passes, expected 3 and 1", moves, passes)
+	}
+}
@@ -0,0 +1,259 @@
+/*
+Package tsumego provides a solver for life and death problems.
+
+The attacker wins by capturing the target chain, the defender by keeping it
+on the board until both players pass. Moves are limited to a region of
+interest; either player may pass instead. Ko is solved twice, once with the
+defender unable to take a ko and once with the attacker unable to: the
+problem is a ko when the side allowed to take kos wins both times.
+*/
+package tsumego
+
+import (
+	"errors"
+
+	"github.com/gosharplite/goxit/pkg/board"
+)
+
+// An Outcome is the result of a problem.
+type Outcome int
+
+// Outcomes of a problem.
+const (
+	Live Outcome = iota
+	Kill
+	Ko
+)
+
+func (o Outcome) String() string {
+
+	switch o {
+	case Live:
+		return "live"
+	case Kill:
+		return "kill"
+	}
+
+	return "ko"
+}
+
+// Pass is the move of a player who passes.
+const Pass = 0
+
+// A Result is the outcome of a problem with its principal variation, the
+// moves of best play starting with the first player, Pass for a pass. For a
+// ko, the variation is the one where the first player wins the ko.
+type Result struct {
+	Outcome Outcome
+	PV      []int
+}
+
+// A Solver solves life and death problems.
+type Solver struct {
+
+	// Number of moves searched, passes included. Lines longer than this are
+	// won by the defender.
+	MaxDepth int
+
+	region   []int
+	target   int
+	attacker bool
+
+	// Side allowed to take kos.
+	attackerTakesKo bool
+
+	// Solved positions, see key.
+	memo map[uint64]node
+}
+
+type node struct {
+	attackerWins bool
+	pv           []int
+}
+
+// NewSolver create a Solver object.
+func NewSolver() *Solver {
+
+	return &Solver{
+		MaxDepth: 40,
+	}
+}
+
+// Solve decides whether the chain on target can be killed, playing only on
+// the empty points of region, with the attacker moving first if
+// attackerFirst is true. The board is restored before returning.
+func (s *Solver) Solve(bd *board.Board, region []int, target int, attackerFirst bool) (Result, error) {
+
+	if bd.IsEmpty(target) {
+		return Result{}, errors.New("tsumego: no stone on target")
+	}
+
+	s.region = region
+	s.target = target
+	s.attacker = !bd.IsBlack(target)
+
+	s.attackerTakesKo = true
+	strong := s.search(bd, attackerFirst, s.MaxDepth)
+
+	if !strong.attackerWins {
+		return Result{Outcome: Live, PV: strong.pv}, nil
+	}
+
+	s.attackerTakesKo = false
+	weak := s.search(bd, attackerFirst, s.MaxDepth)
+
+	if weak.attackerWins {
+		return Result{Outcome: Kill, PV: weak.pv}, nil
+	}
+
+	if attackerFirst {
+		return Result{Outcome: Ko, PV: strong.pv}, nil
+	}
+
+	return Result{Outcome: Ko, PV: weak.pv}, nil
+}
+
+// search solves the position with a fresh table.
+func (s *Solver) search(bd *board.Board, attackerToPlay bool, depth int) node {
+
+	s.memo = map[uint64]node{}
+
+	return s.solve(bd, attackerToPlay, depth, false)
+}
+
+// solve returns whether the attacker wins with a player to move, given the
+// remaining depth and whether the last move was a pass.
+func (s *Solver) solve(bd *board.Board, attackerToPlay bool, depth int, passed bool) node {
+
+	if bd.IsEmpty(s.target) {
+		return node{attackerWins: true}
+	}
+
+	if depth == 0 {
+		return node{}
+	}
+
+	k := s.key(bd, attackerToPlay == s.attacker, depth, passed)
+	if n, ok := s.memo[k]; ok {
+		return n
+	}
+
+	isBlack := attackerToPlay == s.attacker
+
+	var best node
+	found := false
+
+	for _, pt := range s.moves(bd) {
+
+		var child node
+
+		switch {
+		case pt == Pass && passed:
+			// Two passes end the game with the target on the board.
+		case pt == Pass:
+			child = s.solve(bd, !attackerToPlay, depth-1, true)
+		default:
+			if !s.play(bd, pt, isBlack, attackerToPlay) {
+				continue
+			}
+
+			child = s.solve(bd, !attackerToPlay, depth-1, false)
+
+			bd.Undo()
+		}
+
+		child.pv = append([]int{pt}, child.pv...)
+
+		if !found || child.attackerWins == attackerToPlay && best.attackerWins != attackerToPlay {
+			best = child
+			found = true
+		}
+
+		// The player to move has found a win.
+		if best.attackerWins == attackerToPlay {
+			break
+		}
+	}
+
+	s.memo[k] = best
+
+	return best
+}
+
+// play plays a move unless it is illegal or a ko capture the player may not
+// make, and reports whether it was played.
+func (s *Solver) play(bd *board.Board, pt int, isBlack, attackerToPlay bool) bool {
+
+	byBlack, byWhite := bd.Prisoners()
+
+	if bd.Do(pt, isBlack) != nil {
+		return false
+	}
+
+	// The board sets a ko point whenever a lone stone captures one stone; it
+	// is a ko only if the capturing stone is left in atari.
+	b, w := bd.Prisoners()
+	captured := b - byBlack + w - byWhite
+
+	if bd.KoPoint() != 0 && captured == 1 && bd.NumLiberties(pt) == 1 && attackerToPlay != s.attackerTakesKo {
+		bd.Undo()
+		return false
+	}
+
+	return true
+}
+
+// moves returns the empty points of the region, liberties of the target
+// first, then a pass.
+func (s *Solver) moves(bd *board.Board) []int {
+
+	var r []int
+
+	libs := bd.Liberties(s.target)
+
+	for _, pt := range libs {
+		if s.inRegion(pt) {
+			r = append(r, pt)
+		}
+	}
+
+	for _, pt := range s.region {
+
+		if !bd.IsEmpty(pt) || contains(libs, pt) {
+			continue
+		}
+
+		r = append(r, pt)
+	}
+
+	return append(r, Pass)
+}
+
+func (s *Solver) inRegion(pt int) bool {
+
+	return contains(s.region, pt)
+}
+
+// key identifies a position with the color to move, the ko point, the
+// remaining depth and a pending pass.
+func (s *Solver) key(bd *board.Board, isBlack bool, depth int, passed bool) uint64 {
+
+	k := bd.Key(isBlack) ^ uint64(depth)*0xbf58476d1ce4e5b9
+
+	if passed {
+		k ^= 0x94d049bb133111eb
+	}
+
+	return k
+}
+
+func contains(pts []int, pt int) bool {
+
+	for _, p := range pts {
+		if p == pt {
+			return true
+		}
+	}
+
+	return false
+}
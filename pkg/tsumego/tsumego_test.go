@@ -0,0 +1,159 @@
+package tsumego
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gosharplite/goxit/pkg/board"
+)
+
+// problems are set in the top left corner of a 7x7 board. The empty points
+// of the diagram are the region; the target is white.
+var problems = map[string]struct {
+	diagram       string
+	target        [2]int
+	attackerFirst bool
+	outcome       Outcome
+	first         [2]int
+}{
+	"straight three, black kills": {
+		diagram: `
+		...OX
+		OOOOX
+		XXXXX`,
+		target:        [2]int{0, 1},
+		attackerFirst: true,
+		outcome:       Kill,
+		first:         [2]int{1, 0},
+	},
+	"straight three, white lives": {
+		diagram: `
+		...OX
+		OOOOX
+		XXXXX`,
+		target:  [2]int{0, 1},
+		outcome: Live,
+		first:   [2]int{1, 0},
+	},
+	"two eyes": {
+		diagram: `
+		.O.OX
+		OOOOX
+		XXXXX`,
+		target:        [2]int{0, 1},
+		attackerFirst: true,
+		outcome:       Live,
+		first:         [2]int{-1, -1},
+	},
+	"bent three in the corner": {
+		diagram: `
+		..OX
+		.OOX
+		OOXX
+		XXX.`,
+		target:        [2]int{0, 2},
+		attackerFirst: true,
+		outcome:       Kill,
+		first:         [2]int{0, 0},
+	},
+	// White captures at (3, 0) and the capturing stone is in atari.
+	"ko for the second eye": {
+		diagram: `
+		.OX.X
+		OOOXX
+		XXXX.`,
+		target:  [2]int{0, 1},
+		outcome: Ko,
+		first:   [2]int{3, 0},
+	},
+	"ko in atari, black first": {
+		diagram: `
+		.OX.X
+		OOOXX
+		XXXX.`,
+		target:        [2]int{0, 1},
+		attackerFirst: true,
+		outcome:       Kill,
+		first:         [2]int{0, 0},
+	},
+	// Black captures at (1, 0) and keeps two liberties: no ko.
+	"capture out of atari": {
+		diagram: `
+		O.
+		X`,
+		target:        [2]int{0, 0},
+		attackerFirst: true,
+		outcome:       Kill,
+		first:         [2]int{1, 0},
+	},
+}
+
+// setup places a diagram in the top left corner and returns its empty
+// points.
+func setup(t *testing.T, bd *board.Board, diagram string) []int {
+
+	var region []int
+
+	for y, row := range strings.Fields(diagram) {
+		for x, c := range row {
+
+			pt := bd.Point(x, y)
+
+			var err error
+
+			switch c {
+			case 'X':
+				err = bd.DoBlack(pt)
+			case 'O':
+				err = bd.DoWhite(pt)
+			default:
+				region = append(region, pt)
+			}
+
+			if err != nil {
+				t.Fatalf("(%d, %d): %v", x, y, err)
+			}
+		}
+	}
+
+	return region
+}
+
+func TestSolve(t *testing.T) {
+
+	for k, tc := range problems {
+
+		bd := board.NewBoard(7)
+
+		region := setup(t, &bd, tc.diagram)
+		before := bd.String()
+
+		r, err := NewSolver().Solve(&bd, region, bd.Point(tc.target[0], tc.target[1]), tc.attackerFirst)
+		if err != nil {
+			t.Fatalf("%s: %v", k, err)
+		}
+
+		if r.Outcome != tc.outcome {
+			t.Errorf("%s: %v, expected %v", k, r.Outcome, tc.outcome)
+		}
+
+		if len(r.PV) == 0 {
+			t.Errorf("%s: no principal variation", k)
+		} else if x, y := bd.XY(r.PV[0]); x != tc.first[0] || y != tc.first[1] {
+			t.Errorf("%s: first move (%d, %d), expected (%d, %d)", k, x, y, tc.first[0], tc.first[1])
+		}
+
+		if bd.String() != before {
+			t.Errorf("%s: board not restored\n%s", k, bd.String())
+		}
+	}
+}
+
+func TestSolveError(t *testing.T) {
+
+	bd := board.NewBoard(7)
+
+	if _, err := NewSolver().Solve(&bd, nil, bd.Point(0, 0), true); err == nil {
+		t.Error("solved without a target stone")
+	}
+}
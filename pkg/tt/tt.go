@@ -0,0 +1,210 @@
+/*
+Package tt provides a transposition table for game tree search.
+
+The table has a fixed number of entries, allocated once, in buckets of four.
+A position may be stored in any entry of the bucket its key selects. When a
+bucket is full, the entry from the oldest search with the fewest visits is
+replaced. Buckets are guarded by a fixed set of locks, each shared by many
+buckets, so goroutines searching different positions rarely wait for each
+other.
+*/
+package tt
+
+import (
+	"sync"
+)
+
+const (
+	bucketSize = 4
+	numLocks   = 256
+)
+
+// An Entry contains the search statistics of a position.
+type Entry struct {
+	Key uint64
+
+	// Number of simulations through the position.
+	Visits uint32
+
+	// Sum of the results of those simulations, from 0 to 1 each. A float32
+	// would stop counting wins past 2^24 visits.
+	Value float64
+
+	// Best move found, 0 if none.
+	Best int32
+
+	// Search generation which last used the entry, 0 if the entry is
+	// unused.
+	generation uint32
+}
+
+// Mean returns the mean result, 0 if the position was not visited.
+func (e Entry) Mean() float64 {
+
+	if e.Visits == 0 {
+		return 0
+	}
+
+	return e.Value / float64(e.Visits)
+}
+
+type bucket [bucketSize]Entry
+
+// A Table is a transposition table. It is safe for concurrent use.
+type Table struct {
+	buckets []bucket
+	mask    uint64
+
+	locks [numLocks]sync.Mutex
+
+	// Generation of the current search, never 0.
+	generation uint32
+}
+
+// New create a Table object with room for at least the given number of
+// entries, rounded up to a power of two.
+func New(entries int) *Table {
+
+	n := 1
+	for n*bucketSize < entries {
+		n *= 2
+	}
+
+	return &Table{
+		buckets:    make([]bucket, n),
+		mask:       uint64(n - 1),
+		generation: 1,
+	}
+}
+
+// Len returns the number of entries the table holds.
+func (t *Table) Len() int {
+
+	return len(t.buckets) * bucketSize
+}
+
+// NewSearch starts a new generation. Entries not used since are replaced
+// first.
+func (t *Table) NewSearch() {
+
+	for i := range t.locks {
+		t.locks[i].Lock()
+	}
+
+	t.generation++
+
+	// 0 marks unused entries.
+	if t.generation == 0 {
+		t.generation = 1
+	}
+
+	for i := range t.locks {
+		t.locks[i].Unlock()
+	}
+}
+
+// Clear removes every entry.
+func (t *Table) Clear() {
+
+	for i := range t.locks {
+		t.locks[i].Lock()
+	}
+
+	for i := range t.buckets {
+		t.buckets[i] = bucket{}
+	}
+
+	for i := range t.locks {
+		t.locks[i].Unlock()
+	}
+}
+
+// lock locks the bucket of a key and returns it.
+func (t *Table) lock(key uint64) (*bucket, *sync.Mutex) {
+
+	i := key & t.mask
+	m := &t.locks[i%numLocks]
+
+	m.Lock()
+
+	return &t.buckets[i], m
+}
+
+// Probe returns the entry of a key.
+func (t *Table) Probe(key uint64) (Entry, bool) {
+
+	b, m := t.lock(key)
+	defer m.Unlock()
+
+	for _, e := range b {
+		if e.generation != 0 && e.Key == key {
+			return e, true
+		}
+	}
+
+	return Entry{}, false
+}
+
+// Add records a simulation through a position with a result from 0 to 1,
+// storing the position if needed.
+func (t *Table) Add(key uint64, result float64) {
+
+	b, m := t.lock(key)
+	defer m.Unlock()
+
+	e := t.find(b, key)
+
+	e.Visits++
+	e.Value += result
+}
+
+// SetBest records the best move of a position, storing it if needed.
+func (t *Table) SetBest(key uint64, best int) {
+
+	b, m := t.lock(key)
+	defer m.Unlock()
+
+	e := t.find(b, key)
+
+	e.Best = int32(best)
+}
+
+// find returns the entry of a key in a locked bucket, replacing the least
+// valuable entry if the key is missing.
+func (t *Table) find(b *bucket, key uint64) *Entry {
+
+	var r *Entry
+
+	for i := range b {
+
+		e := &b[i]
+
+		if e.generation != 0 && e.Key == key {
+			e.generation = t.generation
+			return e
+		}
+
+		if r == nil || t.worse(e, r) {
+			r = e
+		}
+	}
+
+	*r = Entry{Key: key, generation: t.generation}
+
+	return r
+}
+
+// worse reports whether a is replaced before b: unused entries first, then
+// entries of older searches, then those with fewer visits.
+func (t *Table) worse(a, b *Entry) bool {
+
+	if (a.generation == 0) != (b.generation == 0) {
+		return a.generation == 0
+	}
+
+	if (a.generation == t.generation) != (b.generation == t.generation) {
+		return a.generation != t.generation
+	}
+
+	return a.Visits < b.Visits
+}
@@ -0,0 +1,158 @@
+package tt
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestTable(t *testing.T) {
+
+	tb := New(1000)
+
+	if tb.Len() != 1024 {
+		t.Errorf("%d entries, expected 1024", tb.Len())
+	}
+
+	if _, ok := tb.Probe(7); ok {
+		t.Error("found a key in an empty table")
+	}
+
+	tb.Add(7, 1)
+	tb.Add(7, 0)
+	tb.Add(7, 1)
+	tb.SetBest(7, 42)
+
+	e, ok := tb.Probe(7)
+	if !ok || e.Visits != 3 || e.Best != 42 {
+		t.Errorf("entry %+v, expected 3 visits and best 42", e)
+	}
+
+	if m := e.Mean(); m < 0.66 || m > 0.67 {
+		t.Errorf("mean %v, expected 2/3", m)
+	}
+
+	tb.SetBest(9, 3)
+
+	if e, ok := tb.Probe(9); !ok || e.Best != 3 || e.Visits != 0 {
+		t.Errorf("entry %+v, expected best 3 without visits", e)
+	}
+
+	tb.Clear()
+
+	if _, ok := tb.Probe(7); ok {
+		t.Error("found a key after Clear")
+	}
+}
+
+func TestManyVisits(t *testing.T) {
+
+	tb := New(4)
+
+	tb.Add(7, 1)
+
+	b, m := tb.lock(7)
+	e := tb.find(b, 7)
+	e.Visits, e.Value = 1<<24, 1<<24
+	m.Unlock()
+
+	for i := 0; i < 100; i++ {
+		tb.Add(7, 1)
+	}
+
+	if e, _ := tb.Probe(7); e.Mean() != 1 {
+		t.Errorf("mean %v after %d wins, expected 1", e.Mean(), e.Visits)
+	}
+}
+
+func TestReplacement(t *testing.T) {
+
+	// A single bucket.
+	tb := New(bucketSize)
+
+	for k := uint64(1); k <= bucketSize; k++ {
+		for i := uint64(0); i < k; i++ {
+			tb.Add(k, 1)
+		}
+	}
+
+	// The entry with the fewest visits goes.
+	tb.Add(100, 1)
+
+	if _, ok := tb.Probe(1); ok {
+		t.Error("least visited entry kept")
+	}
+
+	for _, k := range []uint64{2, 3, 4, 100} {
+		if _, ok := tb.Probe(k); !ok {
+			t.Errorf("entry %d replaced", k)
+		}
+	}
+
+	// Entries of older searches go first, whatever their visits.
+	tb.NewSearch()
+
+	tb.Add(100, 1)
+	tb.Add(2, 1)
+	tb.Add(3, 1)
+
+	tb.Add(200, 1)
+
+	if _, ok := tb.Probe(4); ok {
+		t.Error("entry of the last search kept")
+	}
+
+	// An entry 256 searches old is still old.
+	for i := 0; i < 255; i++ {
+		tb.NewSearch()
+	}
+
+	tb.Add(100, 1)
+	tb.Add(2, 1)
+	tb.Add(200, 1)
+
+	tb.NewSearch()
+
+	tb.Add(100, 1)
+	tb.Add(2, 1)
+	tb.Add(200, 1)
+
+	tb.Add(300, 1)
+
+	if _, ok := tb.Probe(3); ok {
+		t.Error("entry 257 searches old kept")
+	}
+}
+
+func TestConcurrent(t *testing.T) {
+
+	tb := New(1 << 12)
+
+	var wg sync.WaitGroup
+
+	for g := 0; g < 8; g++ {
+
+		wg.Add(1)
+
+		go func() {
+
+			defer wg.Done()
+
+			for i := 0; i < 1000; i++ {
+				tb.Add(uint64(i%16)*0x9e3779b97f4a7c15, 1)
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	total := uint32(0)
+
+	for i := 0; i < 16; i++ {
+		e, _ := tb.Probe(uint64(i) * 0x9e3779b97f4a7c15)
+		total += e.Visits
+	}
+
+	if total != 8000 {
+		t.Errorf("%d visits, expected 8000", total)
+	}
+}